@@ -0,0 +1,100 @@
+package tilecollider
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLegend returns the default rune to tile ID mapping of ParseASCII.
+// '.' is empty (0), '#' is solid (1) and '=' is one-way (2).
+func DefaultLegend[T Integer]() map[rune]T {
+	return map[rune]T{'.': 0, '#': 1, '=': 2}
+}
+
+// ParseASCII parses a multi-line ASCII-art map into a [y][x]T tilemap using the legend.
+// Leading and trailing blank lines and the indentation common to all rows are removed,
+// so the map can be indented in raw string literals. Other whitespace is part of the map.
+// All rows must have the same length. If legend is nil, DefaultLegend is used.
+func ParseASCII[T Integer](s string, legend map[rune]T) ([][]T, error) {
+	if legend == nil {
+		legend = DefaultLegend[T]()
+	}
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	indent := commonIndent(lines)
+
+	tileMap := make([][]T, 0, len(lines))
+	for y, line := range lines {
+		line = line[len(indent):]
+		row := make([]T, 0, len(line))
+		for _, r := range line {
+			id, ok := legend[r]
+			if !ok {
+				return nil, fmt.Errorf("tilecollider: unmapped rune %q at %d,%d", r, len(row), y)
+			}
+			row = append(row, id)
+		}
+		if y > 0 && len(row) != len(tileMap[0]) {
+			return nil, fmt.Errorf("tilecollider: row %d has %d tiles, want %d", y, len(row), len(tileMap[0]))
+		}
+		tileMap = append(tileMap, row)
+	}
+	return tileMap, nil
+}
+
+// commonIndent returns the leading spaces and tabs shared by all lines
+func commonIndent(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	indent := lines[0][:len(lines[0])-len(strings.TrimLeft(lines[0], " \t"))]
+	for _, line := range lines[1:] {
+		n := 0
+		for n < len(indent) && n < len(line) && line[n] == indent[n] {
+			n++
+		}
+		indent = indent[:n]
+	}
+	return indent
+}
+
+// NewColliderASCII creates a new tile collider from an ASCII-art map. See ParseASCII.
+func NewColliderASCII[T Integer](s string, legend map[rune]T, tileWidth, tileHeight int) (*Collider[T], error) {
+	tileMap, err := ParseASCII(s, legend)
+	if err != nil {
+		return nil, err
+	}
+	return NewCollider(tileMap, tileWidth, tileHeight), nil
+}
+
+// FormatASCII formats the tilemap as ASCII art using the legend. It is the inverse of ParseASCII.
+// If several runes map to the same ID, the smallest rune is used. Unmapped IDs are printed as '?'.
+// If legend is nil, DefaultLegend is used.
+func FormatASCII[T Integer](tileMap [][]T, legend map[rune]T) string {
+	if legend == nil {
+		legend = DefaultLegend[T]()
+	}
+	runes := make(map[T]rune, len(legend))
+	for r, id := range legend {
+		if old, ok := runes[id]; !ok || r < old {
+			runes[id] = r
+		}
+	}
+	var sb strings.Builder
+	for _, row := range tileMap {
+		for _, id := range row {
+			if r, ok := runes[id]; ok {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteByte('?')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,95 @@
+package tilecollider
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files in testdata")
+
+// checkGolden compares got with the golden file, or rewrites the file with -update
+func checkGolden(t *testing.T, path, got string) {
+	t.Helper()
+	if *update {
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s mismatch\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+func TestParseASCII(t *testing.T) {
+	spaceLegend := map[rune]int{' ': 0, '.': 3, '#': 1}
+	tests := []struct {
+		name   string
+		src    string
+		legend map[rune]int
+		want   [][]int
+	}{
+		{"indented raw string", `
+			..#.
+			.==#
+		`, nil, [][]int{{0, 0, 1, 0}, {0, 2, 2, 1}}},
+		{"only common indentation is removed", "\t\t#.\n\t\t\t.", map[rune]int{'\t': 7, '.': 0, '#': 1}, [][]int{{1, 0}, {7, 0}}},
+		{"spaces in the legend are tiles", " .#\n#. ", spaceLegend, [][]int{{0, 3, 1}, {1, 3, 0}}},
+		{"CRLF line endings", "#.\r\n.#\r\n", nil, [][]int{{1, 0}, {0, 1}}},
+		{"empty", "\n\n", nil, [][]int{}},
+	}
+	for _, tt := range tests {
+		got, err := ParseASCII(tt.src, tt.legend)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseASCIIErrors(t *testing.T) {
+	for _, src := range []string{
+		"..\n...",    // ragged rows
+		"  ..\n ...", // ragged rows after removing the common indentation
+		"..\n.x",     // unmapped rune
+		"#. \n#.",    // trailing space without a legend entry
+	} {
+		if m, err := ParseASCII[int](src, nil); err == nil {
+			t.Errorf("%q parsed as %v", src, m)
+		}
+	}
+}
+
+func TestFormatASCIIGolden(t *testing.T) {
+	c, err := NewColliderASCII[int](`
+		..........
+		..........
+		....==....
+		..........
+		##########
+	`, nil, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.FillRect(1, 1, 2, 3, 1)
+	c.SetTile(8, 2, 2)
+	c.SetTile(9, 4, 5) // unmapped ID
+
+	got := FormatASCII(c.TileMap, nil)
+	checkGolden(t, "testdata/format_ascii.golden", got)
+
+	// Printing and parsing again gives back the same map, except for unmapped IDs
+	c.SetTile(9, 4, 1)
+	back, err := ParseASCII[int](FormatASCII(c.TileMap, nil), nil)
+	if err != nil || !reflect.DeepEqual(back, c.TileMap) {
+		t.Errorf("round trip got %v, %v", back, err)
+	}
+}
@@ -0,0 +1,124 @@
+package tilecollider
+
+import (
+	"container/list"
+	"fmt"
+	"math"
+)
+
+// ChunkLoader returns the tiles of the chunk at the given chunk coordinates in row-major order.
+// Returning nil creates an empty chunk filled with zero values.
+type ChunkLoader[T Integer] func(chunkX, chunkY int) []T
+
+// ChunkMap is an unbounded TileSource made of fixed-size chunks that are loaded on demand.
+// Chunk coordinates can be negative. The least recently used chunks are evicted when MaxChunks is exceeded.
+type ChunkMap[T Integer] struct {
+	ChunkSize [2]int                              // Width and height of chunks in tiles. Both must be positive.
+	MaxChunks int                                 // Maximum number of chunks kept in memory. 0 means no limit.
+	Loader    ChunkLoader[T]                      // Called for missing chunks
+	OnEvict   func(chunkX, chunkY int, tiles []T) // Optional. Called before a chunk is evicted.
+
+	chunks map[[2]int]*list.Element
+	lru    *list.List
+}
+
+type chunk[T Integer] struct {
+	coords [2]int
+	tiles  []T
+}
+
+// NewChunkMap creates a new chunked tilemap with the given chunk size and loader.
+// It panics if the chunk width or height is not positive.
+func NewChunkMap[T Integer](chunkWidth, chunkHeight, maxChunks int, loader ChunkLoader[T]) *ChunkMap[T] {
+	if chunkWidth <= 0 || chunkHeight <= 0 {
+		panic(fmt.Sprintf("tilecollider: invalid chunk size %dx%d", chunkWidth, chunkHeight))
+	}
+	return &ChunkMap[T]{
+		ChunkSize: [2]int{chunkWidth, chunkHeight},
+		MaxChunks: maxChunks,
+		Loader:    loader,
+	}
+}
+
+// Get returns the tile ID at the given tile coordinates, loading the chunk if needed
+func (m *ChunkMap[T]) Get(x, y int) T {
+	tiles, lx, ly := m.locate(x, y)
+	return tiles[ly*m.ChunkSize[0]+lx]
+}
+
+// Set sets the tile ID at the given tile coordinates, loading the chunk if needed
+func (m *ChunkMap[T]) Set(x, y int, id T) {
+	tiles, lx, ly := m.locate(x, y)
+	tiles[ly*m.ChunkSize[0]+lx] = id
+}
+
+// Bounds returns unlimited bounds
+func (m *ChunkMap[T]) Bounds() (minX, minY, maxX, maxY int) {
+	return math.MinInt, math.MinInt, math.MaxInt, math.MaxInt
+}
+
+// Loaded returns the number of chunks in memory
+func (m *ChunkMap[T]) Loaded() int {
+	return len(m.chunks)
+}
+
+// Evict removes all chunks from memory
+func (m *ChunkMap[T]) Evict() {
+	for m.lru != nil && m.lru.Len() > 0 {
+		m.evictOldest()
+	}
+}
+
+// locate returns the chunk tiles containing the tile and the tile coordinates inside the chunk
+func (m *ChunkMap[T]) locate(x, y int) (tiles []T, localX, localY int) {
+	cx, localX := floorDivMod(x, m.ChunkSize[0])
+	cy, localY := floorDivMod(y, m.ChunkSize[1])
+	return m.chunk(cx, cy), localX, localY
+}
+
+// chunk returns the tiles of the chunk, loading it if needed
+func (m *ChunkMap[T]) chunk(cx, cy int) []T {
+	if m.chunks == nil {
+		m.chunks = make(map[[2]int]*list.Element)
+		m.lru = list.New()
+	}
+	key := [2]int{cx, cy}
+	if e, ok := m.chunks[key]; ok {
+		m.lru.MoveToFront(e)
+		return e.Value.(*chunk[T]).tiles
+	}
+
+	size := m.ChunkSize[0] * m.ChunkSize[1]
+	var tiles []T
+	if m.Loader != nil {
+		tiles = m.Loader(cx, cy)
+	}
+	if len(tiles) < size {
+		tiles = append(tiles, make([]T, size-len(tiles))...)
+	}
+
+	m.chunks[key] = m.lru.PushFront(&chunk[T]{coords: key, tiles: tiles})
+	for m.MaxChunks > 0 && m.lru.Len() > m.MaxChunks {
+		m.evictOldest()
+	}
+	return tiles
+}
+
+func (m *ChunkMap[T]) evictOldest() {
+	e := m.lru.Back()
+	ch := m.lru.Remove(e).(*chunk[T])
+	delete(m.chunks, ch.coords)
+	if m.OnEvict != nil {
+		m.OnEvict(ch.coords[0], ch.coords[1], ch.tiles)
+	}
+}
+
+// floorDivMod returns the floored quotient and the non-negative remainder
+func floorDivMod(a, b int) (q, r int) {
+	q, r = a/b, a%b
+	if r < 0 {
+		q--
+		r += b
+	}
+	return
+}
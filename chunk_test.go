@@ -0,0 +1,65 @@
+package tilecollider
+
+import (
+	"fmt"
+	"testing"
+)
+
+// chunkFrom returns a ChunkMap of 3x2 chunks that loads its tiles from m. Tiles outside m are empty.
+func chunkFrom(m [][]uint8, maxChunks int) *ChunkMap[uint8] {
+	return NewChunkMap(3, 2, maxChunks, func(cx, cy int) []uint8 {
+		tiles := make([]uint8, 6)
+		for ly := range 2 {
+			for lx := range 3 {
+				x, y := cx*3+lx, cy*2+ly
+				if y >= 0 && y < len(m) && x >= 0 && x < len(m[0]) {
+					tiles[ly*3+lx] = m[y][x]
+				}
+			}
+		}
+		return tiles
+	})
+}
+
+func TestChunkMapMatchesReference(t *testing.T) {
+	checkEquivalent(t, 20000, func(m [][]uint8, tw, th int) *Collider[uint8] {
+		return NewColliderSource[uint8](chunkFrom(m, 4), tw, th)
+	})
+}
+
+func TestChunkMapEviction(t *testing.T) {
+	var evicted [][2]int
+	m := NewChunkMap(2, 2, 2, func(cx, cy int) []uint8 { return nil })
+	m.OnEvict = func(cx, cy int, tiles []uint8) { evicted = append(evicted, [2]int{cx, cy}) }
+
+	m.Set(-1, -1, 3) // chunk -1,-1
+	m.Get(2, 0)      // chunk 1,0
+	m.Get(-2, -2)    // touches chunk -1,-1 again
+	m.Get(0, 4)      // chunk 0,2 evicts 1,0
+	if m.Loaded() != 2 {
+		t.Fatalf("loaded %d chunks, want 2", m.Loaded())
+	}
+	if len(evicted) != 1 || evicted[0] != [2]int{1, 0} {
+		t.Fatalf("evicted %v, want [[1 0]]", evicted)
+	}
+	if id := m.Get(-1, -1); id != 3 {
+		t.Errorf("got tile %d, want 3", id)
+	}
+	m.Evict()
+	if m.Loaded() != 0 || len(evicted) != 3 {
+		t.Errorf("Evict left %d chunks, evicted %v", m.Loaded(), evicted)
+	}
+}
+
+func TestNewChunkMapPanics(t *testing.T) {
+	for _, size := range [][2]int{{0, 4}, {4, 0}, {-2, 4}} {
+		t.Run(fmt.Sprint(size), func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("NewChunkMap(%d, %d) did not panic", size[0], size[1])
+				}
+			}()
+			NewChunkMap[uint8](size[0], size[1], 0, nil)
+		})
+	}
+}
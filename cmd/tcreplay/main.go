@@ -0,0 +1,79 @@
+// Command tcreplay replays a tilecollider recording made with replay.Recorder against the current version
+// of the library and reports diverging calls.
+//
+// Usage:
+//
+//	tcreplay recording.tcrp
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/setanarut/tilecollider"
+	"github.com/setanarut/tilecollider/replay"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: tcreplay recording.tcrp")
+		os.Exit(2)
+	}
+	f, err := os.Open(os.Args[1])
+	if err != nil {
+		fatal(err)
+	}
+	defer f.Close()
+
+	bits, signed, err := replay.ReadKind(f)
+	if err != nil {
+		fatal(err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		fatal(err)
+	}
+
+	var diverged, calls int
+	switch {
+	case bits == 8 && signed:
+		diverged, calls, err = run[int8](f)
+	case bits == 16 && signed:
+		diverged, calls, err = run[int16](f)
+	case bits == 32 && signed:
+		diverged, calls, err = run[int32](f)
+	case bits == 64 && signed:
+		diverged, calls, err = run[int64](f)
+	case bits == 8:
+		diverged, calls, err = run[uint8](f)
+	case bits == 16:
+		diverged, calls, err = run[uint16](f)
+	case bits == 32:
+		diverged, calls, err = run[uint32](f)
+	case bits == 64:
+		diverged, calls, err = run[uint64](f)
+	default:
+		err = fmt.Errorf("unsupported tile type: %d-bit signed=%v", bits, signed)
+	}
+	if err != nil {
+		fatal(err)
+	}
+
+	fmt.Printf("%d calls replayed, %d diverged\n", calls, diverged)
+	if diverged > 0 {
+		os.Exit(1)
+	}
+}
+
+func run[T tilecollider.Integer](r io.Reader) (int, int, error) {
+	divergences, calls, err := replay.Replay[T](r)
+	for _, d := range divergences {
+		fmt.Println(d)
+	}
+	return len(divergences), calls, err
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "tcreplay:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,99 @@
+package tilecollider
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// ReadCSV reads a [y][x]T tilemap from CSV. Both plain spreadsheet CSV and Tiled's CSV layer format
+// (every row but the last ends with a trailing comma) are accepted. Trailing commas are only stripped
+// when all rows except possibly the last have one; any other empty field is an error.
+// Rows must have the same length and values must fit in T.
+func ReadCSV[T Integer](r io.Reader) ([][]T, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("tilecollider: %w", err)
+	}
+	if trailingComma(records) {
+		for y, record := range records {
+			if len(record) > 1 && strings.TrimSpace(record[len(record)-1]) == "" {
+				records[y] = record[:len(record)-1]
+			}
+		}
+	}
+	bits, signed := intKind[T]()
+
+	tileMap := make([][]T, 0, len(records))
+	for y, record := range records {
+		row := make([]T, len(record))
+		for x, field := range record {
+			field = strings.TrimSpace(field)
+			if signed {
+				v, err := strconv.ParseInt(field, 10, bits)
+				if err != nil {
+					return nil, fmt.Errorf("tilecollider: tile %d,%d: %w", x, y, err)
+				}
+				row[x] = T(v)
+			} else {
+				v, err := strconv.ParseUint(field, 10, bits)
+				if err != nil {
+					return nil, fmt.Errorf("tilecollider: tile %d,%d: %w", x, y, err)
+				}
+				row[x] = T(v)
+			}
+		}
+		if y > 0 && len(row) != len(tileMap[0]) {
+			return nil, fmt.Errorf("tilecollider: row %d has %d tiles, want %d", y, len(row), len(tileMap[0]))
+		}
+		tileMap = append(tileMap, row)
+	}
+	return tileMap, nil
+}
+
+// trailingComma reports whether records use Tiled's trailing comma, i.e. every record
+// except possibly the last ends with an empty field
+func trailingComma(records [][]string) bool {
+	if len(records) == 0 {
+		return false
+	}
+	rows := records[:len(records)-1]
+	if len(rows) == 0 {
+		rows = records
+	}
+	for _, record := range rows {
+		if len(record) < 2 || strings.TrimSpace(record[len(record)-1]) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// WriteCSV writes the tilemap as CSV with one row per line
+func WriteCSV[T Integer](w io.Writer, tileMap [][]T) error {
+	cw := csv.NewWriter(w)
+	var record []string
+	for _, row := range tileMap {
+		record = record[:0]
+		for _, id := range row {
+			record = append(record, fmt.Sprint(id))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// intKind returns the size in bits and the signedness of T
+func intKind[T Integer]() (bits int, signed bool) {
+	var zero T
+	return int(unsafe.Sizeof(zero)) * 8, ^zero < 0
+}
@@ -0,0 +1,87 @@
+package tilecollider
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want [][]uint8
+	}{
+		{"plain", "1,0,2\n0,0,3\n", [][]uint8{{1, 0, 2}, {0, 0, 3}}},
+		{"tiled", "1,0,2,\n0,0,3,\n4,4,4\n", [][]uint8{{1, 0, 2}, {0, 0, 3}, {4, 4, 4}}},
+		{"tiled last comma", "1,0,\n0,3,\n", [][]uint8{{1, 0}, {0, 3}}},
+		{"tiled single row", "1,0,2,\n", [][]uint8{{1, 0, 2}}},
+		{"spaces", " 1, 0\n0 ,2 \n", [][]uint8{{1, 0}, {0, 2}}},
+		{"crlf", "1,0\r\n0,2\r\n", [][]uint8{{1, 0}, {0, 2}}},
+		{"empty", "", [][]uint8{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadCSV[uint8](strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadCSVErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+	}{
+		{"mixed trailing comma", "1,0,\n0,2\n3,3,\n"},
+		{"trailing comma on last row only", "1,0\n0,2,\n"},
+		{"ragged", "1,0,2\n0,2\n"},
+		{"empty field", "1,,2\n0,2,3\n"},
+		{"not a number", "1,x\n"},
+		{"overflow", "256,0\n"},
+		{"negative unsigned", "-1,0\n"},
+		{"bad quote", "1,\"0\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if m, err := ReadCSV[uint8](strings.NewReader(tt.in)); err == nil {
+				t.Errorf("got %v, want error", m)
+			}
+		})
+	}
+}
+
+func TestReadCSVSigned(t *testing.T) {
+	got, err := ReadCSV[int16](strings.NewReader("-1,32767\n-32768,0\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]int16{{-1, 32767}, {-32768, 0}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := ReadCSV[int16](strings.NewReader("32768\n")); err == nil {
+		t.Error("want overflow error")
+	}
+}
+
+func TestWriteCSVRoundTrip(t *testing.T) {
+	m := randMap(rand.New(rand.NewSource(1)), 7, 5)
+	var buf bytes.Buffer
+	if err := WriteCSV(&buf, m); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadCSV[uint8](&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Errorf("got %v, want %v", got, m)
+	}
+}
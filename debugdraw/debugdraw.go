@@ -0,0 +1,264 @@
+// Package debugdraw renders tilecollider collision scenes headlessly to images using only the standard library.
+//
+// It is meant for CI tests and bug reports, e.g. to save a PNG snapshot when an assertion fails.
+package debugdraw
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// Body is a rectangle of a scene with its attempted and allowed movement
+type Body struct {
+	Rect    [4]float64 // X, Y, width and height
+	Move    [2]float64 // Attempted movement
+	Allowed [2]float64 // Allowed movement returned by Collide
+}
+
+// Scene is a collision scene to draw
+type Scene[T tilecollider.Integer] struct {
+	Collider   *tilecollider.Collider[T]       // Tilemap and collision settings
+	Bodies     []Body                          // Bodies to draw
+	Collisions []tilecollider.CollisionInfo[T] // Collisions to draw. If nil, Collider.Collisions is used.
+	View       [4]float64                      // X, Y, width and height of the drawn area in world pixels. If zero, the whole map is drawn.
+	Scale      float64                         // Image pixels per world pixel. If zero, 1 is used.
+}
+
+// Style is the color scheme used for drawing
+type Style struct {
+	Background color.Color
+	Grid       color.Color
+	Tile       color.Color // Solid tiles
+	Fluid      color.Color // Fluid tiles. If nil, fluids are not drawn.
+	Collided   color.Color // Tiles in the collision list
+	Normal     color.Color // Collision normals
+	Body       color.Color // Bodies at their start position
+	Move       color.Color // Attempted movement and target rectangle
+	Allowed    color.Color // Allowed movement and final rectangle
+}
+
+// DefaultStyle is the default color scheme
+var DefaultStyle = Style{
+	Background: color.RGBA{24, 24, 32, 255},
+	Grid:       color.RGBA{48, 48, 60, 255},
+	Tile:       color.RGBA{127, 127, 127, 255},
+	Fluid:      color.RGBA{40, 90, 200, 255},
+	Collided:   color.RGBA{255, 255, 0, 255},
+	Normal:     color.RGBA{255, 0, 255, 255},
+	Body:       color.RGBA{0, 160, 255, 255},
+	Move:       color.RGBA{255, 60, 60, 255},
+	Allowed:    color.RGBA{60, 255, 60, 255},
+}
+
+// Render draws the scene to a new image with DefaultStyle
+func Render[T tilecollider.Integer](s Scene[T]) *image.RGBA {
+	return RenderStyle(s, DefaultStyle)
+}
+
+// RenderStyle draws the scene to a new image with the given style
+func RenderStyle[T tilecollider.Integer](s Scene[T], style Style) *image.RGBA {
+	view, scale := s.view(), s.scale()
+	img := image.NewRGBA(image.Rect(0, 0, int(math.Ceil(view[2]*scale)), int(math.Ceil(view[3]*scale))))
+	Draw(img, s, style)
+	return img
+}
+
+// WritePNG renders the scene with DefaultStyle and encodes it as PNG
+func WritePNG[T tilecollider.Integer](w io.Writer, s Scene[T]) error {
+	return png.Encode(w, Render(s))
+}
+
+// Draw draws the scene onto dst with the given style
+func Draw[T tilecollider.Integer](dst draw.Image, s Scene[T], style Style) {
+	c := s.Collider
+	view, scale := s.view(), s.scale()
+	tw, th := float64(c.TileSize[0]), float64(c.TileSize[1])
+	p := painter{dst: dst, view: view, scale: scale}
+
+	p.fill([4]float64{view[0], view[1], view[2], view[3]}, style.Background)
+
+	left, top, right, bottom := tileRange(c, view)
+	for y := top; y < bottom; y++ {
+		for x := left; x < right; x++ {
+			rect := [4]float64{float64(x) * tw, float64(y) * th, tw, th}
+			if id, ok := c.Tile(x, y); ok {
+				if clr, drawn := tileColor(c, id, style); drawn {
+					p.fill(rect, clr)
+				}
+			}
+			p.outline(rect, style.Grid)
+		}
+	}
+
+	collisions := s.Collisions
+	if collisions == nil {
+		collisions = c.Collisions
+	}
+	for _, col := range collisions {
+		rect := [4]float64{float64(col.TileCoords[0]) * tw, float64(col.TileCoords[1]) * th, tw, th}
+		p.outline(rect, style.Collided)
+		cx, cy := rect[0]+tw/2, rect[1]+th/2
+		p.arrow(cx, cy, cx+float64(col.Normal[0])*tw/2, cy+float64(col.Normal[1])*th/2, style.Normal)
+	}
+
+	for _, b := range s.Bodies {
+		cx, cy := b.Rect[0]+b.Rect[2]/2, b.Rect[1]+b.Rect[3]/2
+		p.outline(translate(b.Rect, b.Move), style.Move)
+		p.outline(translate(b.Rect, b.Allowed), style.Allowed)
+		p.outline(b.Rect, style.Body)
+		p.arrow(cx, cy, cx+b.Move[0], cy+b.Move[1], style.Move)
+		p.arrow(cx, cy, cx+b.Allowed[0], cy+b.Allowed[1], style.Allowed)
+	}
+}
+
+// view returns the drawn area in world pixels
+func (s Scene[T]) view() [4]float64 {
+	if s.View[2] > 0 && s.View[3] > 0 {
+		return s.View
+	}
+	c := s.Collider
+	minX, minY, maxX, maxY := c.Bounds()
+	if minX != math.MinInt && minY != math.MinInt && maxX != math.MaxInt && maxY != math.MaxInt {
+		return [4]float64{
+			float64(minX * c.TileSize[0]), float64(minY * c.TileSize[1]),
+			float64((maxX - minX) * c.TileSize[0]), float64((maxY - minY) * c.TileSize[1]),
+		}
+	}
+
+	// Unbounded map: fit the bodies with a margin of two tiles
+	left, top, right, bottom := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, b := range s.Bodies {
+		for _, r := range [][4]float64{b.Rect, translate(b.Rect, b.Move)} {
+			left, top = min(left, r[0]), min(top, r[1])
+			right, bottom = max(right, r[0]+r[2]), max(bottom, r[1]+r[3])
+		}
+	}
+	if len(s.Bodies) == 0 {
+		left, top, right, bottom = 0, 0, 0, 0
+	}
+	mx, my := 2*float64(c.TileSize[0]), 2*float64(c.TileSize[1])
+	return [4]float64{left - mx, top - my, right - left + 2*mx, bottom - top + 2*my}
+}
+
+func (s Scene[T]) scale() float64 {
+	if s.Scale > 0 {
+		return s.Scale
+	}
+	return 1
+}
+
+// tileRange returns the range of tiles visible in the view. max values are exclusive.
+func tileRange[T tilecollider.Integer](c *tilecollider.Collider[T], view [4]float64) (left, top, right, bottom int) {
+	minX, minY, maxX, maxY := c.Bounds()
+	left = max(minX, int(math.Floor(view[0]/float64(c.TileSize[0]))))
+	top = max(minY, int(math.Floor(view[1]/float64(c.TileSize[1]))))
+	right = min(maxX, int(math.Ceil((view[0]+view[2])/float64(c.TileSize[0]))))
+	bottom = min(maxY, int(math.Ceil((view[1]+view[3])/float64(c.TileSize[1]))))
+	return
+}
+
+// tileColor returns the fill color of the tile ID and whether the tile is drawn. Only solid and fluid tiles are drawn.
+func tileColor[T tilecollider.Integer](c *tilecollider.Collider[T], id T, style Style) (color.Color, bool) {
+	if c.IsSolid(id) {
+		return style.Tile, true
+	}
+	if _, fluid := c.Fluids[id]; fluid && style.Fluid != nil {
+		return style.Fluid, true
+	}
+	return nil, false
+}
+
+func translate(rect [4]float64, move [2]float64) [4]float64 {
+	return [4]float64{rect[0] + move[0], rect[1] + move[1], rect[2], rect[3]}
+}
+
+// painter draws primitives in world coordinates
+type painter struct {
+	dst   draw.Image
+	view  [4]float64
+	scale float64
+}
+
+func (p painter) point(x, y float64) (int, int) {
+	return int(math.Floor((x - p.view[0]) * p.scale)), int(math.Floor((y - p.view[1]) * p.scale))
+}
+
+func (p painter) fill(rect [4]float64, c color.Color) {
+	x0, y0 := p.point(rect[0], rect[1])
+	x1, y1 := p.point(rect[0]+rect[2], rect[1]+rect[3])
+	draw.Draw(p.dst, image.Rect(x0, y0, x1, y1).Intersect(p.dst.Bounds()), image.NewUniform(c), image.Point{}, draw.Over)
+}
+
+func (p painter) outline(rect [4]float64, c color.Color) {
+	x0, y0 := p.point(rect[0], rect[1])
+	x1, y1 := p.point(rect[0]+rect[2], rect[1]+rect[3])
+	x1, y1 = max(x0, x1-1), max(y0, y1-1)
+	p.linePx(x0, y0, x1, y0, c)
+	p.linePx(x1, y0, x1, y1, c)
+	p.linePx(x1, y1, x0, y1, c)
+	p.linePx(x0, y1, x0, y0, c)
+}
+
+// arrow draws a line with a small head at the end
+func (p painter) arrow(x0, y0, x1, y1 float64, c color.Color) {
+	ax, ay := p.point(x0, y0)
+	bx, by := p.point(x1, y1)
+	p.linePx(ax, ay, bx, by, c)
+	dx, dy := float64(bx-ax), float64(by-ay)
+	length := math.Hypot(dx, dy)
+	if length < 1 {
+		return
+	}
+	head := min(4, length/2)
+	ux, uy := dx/length*head, dy/length*head
+	p.linePx(bx, by, bx-int(math.Round(ux-uy)), by-int(math.Round(uy+ux)), c)
+	p.linePx(bx, by, bx-int(math.Round(ux+uy)), by-int(math.Round(uy-ux)), c)
+}
+
+// linePx draws a line in image pixels with Bresenham's algorithm
+func (p painter) linePx(x0, y0, x1, y1 int, c color.Color) {
+	b := p.dst.Bounds()
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := sign(x1-x0), sign(y1-y0)
+	e := dx + dy
+	for {
+		if image.Pt(x0, y0).In(b) {
+			p.dst.Set(x0, y0, c)
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func sign(v int) int {
+	switch {
+	case v < 0:
+		return -1
+	case v > 0:
+		return 1
+	}
+	return 0
+}
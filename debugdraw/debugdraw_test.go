@@ -0,0 +1,107 @@
+package debugdraw
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// testScene returns a 64x48 scene with one body moving right into the solid tile 2,1
+func testScene() Scene[uint8] {
+	c := tilecollider.NewCollider([][]uint8{
+		{0, 0, 0, 0},
+		{0, 0, 1, 0},
+		{0, 0, 0, 0},
+	}, 16, 16)
+	b := Body{Rect: [4]float64{4, 18, 8, 8}, Move: [2]float64{30, 0}}
+	b.Allowed[0], b.Allowed[1] = c.Collide(b.Rect[0], b.Rect[1], b.Rect[2], b.Rect[3], b.Move[0], b.Move[1], nil)
+	return Scene[uint8]{Collider: c, Bodies: []Body{b}}
+}
+
+func rgba(c color.Color) color.RGBA {
+	return color.RGBAModel.Convert(c).(color.RGBA)
+}
+
+func TestRender(t *testing.T) {
+	s := testScene()
+	if s.Bodies[0].Allowed != [2]float64{20, 0} || len(s.Collider.Collisions) != 1 {
+		t.Fatalf("unexpected scene: allowed %v, collisions %v", s.Bodies[0].Allowed, s.Collider.Collisions)
+	}
+	img := Render(s)
+	if got := img.Bounds(); got != image.Rect(0, 0, 64, 48) {
+		t.Fatalf("bounds = %v, want 64x48", got)
+	}
+	tests := []struct {
+		name string
+		x, y int
+		want color.Color
+	}{
+		{"background", 8, 40, DefaultStyle.Background},
+		{"grid", 16, 40, DefaultStyle.Grid},
+		{"tile", 44, 28, DefaultStyle.Tile},
+		{"collided tile outline", 32, 30, DefaultStyle.Collided},
+		{"normal arrow", 38, 24, DefaultStyle.Normal},
+		{"normal arrow head", 36, 28, DefaultStyle.Normal},
+		{"body outline", 4, 20, DefaultStyle.Body},
+		{"attempted rectangle", 41, 20, DefaultStyle.Move},
+		{"allowed rectangle", 24, 20, DefaultStyle.Allowed},
+	}
+	for _, tt := range tests {
+		if got := img.RGBAAt(tt.x, tt.y); got != rgba(tt.want) {
+			t.Errorf("%s at %d,%d = %v, want %v", tt.name, tt.x, tt.y, got, rgba(tt.want))
+		}
+	}
+}
+
+func TestRenderViewAndScale(t *testing.T) {
+	s := testScene()
+	s.View = [4]float64{32, 16, 16, 16}
+	s.Scale = 2
+	img := Render(s)
+	if got := img.Bounds(); got != image.Rect(0, 0, 32, 32) {
+		t.Fatalf("bounds = %v, want 32x32", got)
+	}
+	// The view only contains the solid tile
+	if got := img.RGBAAt(24, 24); got != rgba(DefaultStyle.Tile) {
+		t.Errorf("tile = %v, want %v", got, rgba(DefaultStyle.Tile))
+	}
+}
+
+func TestRenderUnbounded(t *testing.T) {
+	c := tilecollider.NewColliderSource[uint8](tilecollider.NewChunkMap[uint8](8, 8, 0, nil), 16, 16)
+	s := Scene[uint8]{Collider: c, Bodies: []Body{{Rect: [4]float64{100, 200, 10, 10}, Move: [2]float64{30, -20}}}}
+	// The bodies and their targets with a margin of two tiles
+	if got, want := s.view(), [4]float64{68, 148, 104, 94}; got != want {
+		t.Errorf("view = %v, want %v", got, want)
+	}
+	if got := Render(s).Bounds(); got != image.Rect(0, 0, 104, 94) {
+		t.Errorf("bounds = %v, want 104x94", got)
+	}
+}
+
+func TestWritePNG(t *testing.T) {
+	s := testScene()
+	var buf bytes.Buffer
+	if err := WritePNG(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Render(s)
+	if decoded.Bounds() != want.Bounds() {
+		t.Fatalf("bounds = %v, want %v", decoded.Bounds(), want.Bounds())
+	}
+	for y := range want.Bounds().Dy() {
+		for x := range want.Bounds().Dx() {
+			if got := rgba(decoded.At(x, y)); got != want.RGBAAt(x, y) {
+				t.Fatalf("pixel %d,%d = %v, want %v", x, y, got, want.RGBAAt(x, y))
+			}
+		}
+	}
+}
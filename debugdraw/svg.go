@@ -0,0 +1,113 @@
+package debugdraw
+
+import (
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+	"os"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// WriteSVG writes the scene as an SVG document with DefaultStyle
+func WriteSVG[T tilecollider.Integer](w io.Writer, s Scene[T]) error {
+	return WriteSVGStyle(w, s, DefaultStyle)
+}
+
+// SaveSVG writes the scene as an SVG file with DefaultStyle, e.g. to attach visual evidence to a failed test
+func SaveSVG[T tilecollider.Integer](path string, s Scene[T]) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := WriteSVG(f, s); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// WriteSVGStyle writes the scene as an SVG document with the given style. Coordinates are in world pixels.
+func WriteSVGStyle[T tilecollider.Integer](w io.Writer, s Scene[T], style Style) error {
+	c := s.Collider
+	view, scale := s.view(), s.scale()
+	tw, th := float64(c.TileSize[0]), float64(c.TileSize[1])
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%g" height="%g" viewBox="%g %g %g %g">`+"\n",
+		view[2]*scale, view[3]*scale, view[0], view[1], view[2], view[3])
+	for _, m := range []struct {
+		id string
+		c  color.Color
+	}{{"normal", style.Normal}, {"move", style.Move}, {"allowed", style.Allowed}} {
+		fmt.Fprintf(bw, `<defs><marker id="%s" viewBox="0 0 10 10" refX="10" refY="5" markerWidth="6" markerHeight="6" orient="auto-start-reverse"><path d="M0,0 L10,5 L0,10 z" %s/></marker></defs>`+"\n",
+			m.id, svgPaint("fill", m.c))
+	}
+	fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g" %s/>`+"\n", view[0], view[1], view[2], view[3], svgPaint("fill", style.Background))
+
+	// Only solid and fluid tiles get an element, so large maps stay small. The grid is a single pattern.
+	left, top, right, bottom := tileRange(c, view)
+	fmt.Fprintln(bw, `<g id="tiles">`)
+	for y := top; y < bottom; y++ {
+		for x := left; x < right; x++ {
+			id, _ := c.Tile(x, y)
+			clr, drawn := tileColor(c, id, style)
+			if !drawn {
+				continue
+			}
+			fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g" %s><title>%d,%d: %v</title></rect>`+"\n",
+				float64(x)*tw, float64(y)*th, tw, th, svgPaint("fill", clr), x, y, id)
+		}
+	}
+	fmt.Fprintln(bw, `</g>`)
+	if left < right && top < bottom {
+		// The pattern clips half of each line, so the stroke is twice the visible width
+		fmt.Fprintf(bw, `<defs><pattern id="grid" width="%g" height="%g" patternUnits="userSpaceOnUse"><path d="M0,0 H%g M0,0 V%g" fill="none" stroke-width="%g" %s/></pattern></defs>`+"\n",
+			tw, th, tw, th, 2/scale, svgPaint("stroke", style.Grid))
+		fmt.Fprintf(bw, `<rect id="grid-lines" x="%g" y="%g" width="%g" height="%g" fill="url(#grid)" stroke-width="%g" %s/>`+"\n",
+			float64(left)*tw, float64(top)*th, float64(right-left)*tw, float64(bottom-top)*th, 1/scale, svgPaint("stroke", style.Grid))
+	}
+
+	collisions := s.Collisions
+	if collisions == nil {
+		collisions = c.Collisions
+	}
+	fmt.Fprintf(bw, `<g id="collisions" fill="none" stroke-width="%g">`+"\n", 2/scale)
+	for _, col := range collisions {
+		x, y := float64(col.TileCoords[0])*tw, float64(col.TileCoords[1])*th
+		cx, cy := x+tw/2, y+th/2
+		fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g" %s><title>tile %v at %v, normal %v</title></rect>`+"\n",
+			x, y, tw, th, svgPaint("stroke", style.Collided), col.TileID, col.TileCoords, col.Normal)
+		fmt.Fprintf(bw, `<line x1="%g" y1="%g" x2="%g" y2="%g" %s marker-end="url(#normal)"/>`+"\n",
+			cx, cy, cx+float64(col.Normal[0])*tw/2, cy+float64(col.Normal[1])*th/2, svgPaint("stroke", style.Normal))
+	}
+	fmt.Fprintln(bw, `</g>`)
+
+	fmt.Fprintf(bw, `<g id="bodies" fill="none" stroke-width="%g">`+"\n", 1.5/scale)
+	for i, b := range s.Bodies {
+		cx, cy := b.Rect[0]+b.Rect[2]/2, b.Rect[1]+b.Rect[3]/2
+		fmt.Fprintf(bw, `<g><title>body %d: rect %v, move %v, allowed %v</title>`+"\n", i, b.Rect, b.Move, b.Allowed)
+		fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g" %s stroke-dasharray="%g"/>`+"\n",
+			b.Rect[0]+b.Move[0], b.Rect[1]+b.Move[1], b.Rect[2], b.Rect[3], svgPaint("stroke", style.Move), 3/scale)
+		fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g" %s/>`+"\n",
+			b.Rect[0]+b.Allowed[0], b.Rect[1]+b.Allowed[1], b.Rect[2], b.Rect[3], svgPaint("stroke", style.Allowed))
+		fmt.Fprintf(bw, `<rect x="%g" y="%g" width="%g" height="%g" %s/>`+"\n",
+			b.Rect[0], b.Rect[1], b.Rect[2], b.Rect[3], svgPaint("stroke", style.Body))
+		fmt.Fprintf(bw, `<line x1="%g" y1="%g" x2="%g" y2="%g" %s marker-end="url(#move)"/>`+"\n",
+			cx, cy, cx+b.Move[0], cy+b.Move[1], svgPaint("stroke", style.Move))
+		fmt.Fprintf(bw, `<line x1="%g" y1="%g" x2="%g" y2="%g" %s marker-end="url(#allowed)"/>`+"\n",
+			cx, cy, cx+b.Allowed[0], cy+b.Allowed[1], svgPaint("stroke", style.Allowed))
+		fmt.Fprintln(bw, `</g>`)
+	}
+	fmt.Fprintln(bw, `</g>`)
+
+	fmt.Fprintln(bw, `</svg>`)
+	return bw.Flush()
+}
+
+// svgPaint returns a fill or stroke attribute with opacity for the color
+func svgPaint(attr string, c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return fmt.Sprintf(`%s="#%02x%02x%02x" %s-opacity="%.3g"`, attr, n.R, n.G, n.B, attr, float64(n.A)/255)
+}
@@ -0,0 +1,77 @@
+package debugdraw
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+func TestWriteSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, testScene()); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	for _, want := range []string{
+		`width="64" height="48" viewBox="0 0 64 48"`,
+		`<title>2,1: 1</title>`,
+		`<pattern id="grid" width="16" height="16"`,
+		`<rect id="grid-lines" x="0" y="0" width="64" height="48" fill="url(#grid)"`,
+		`<title>tile 1 at [2 1], normal [-1 0]</title>`,
+		`<title>body 0: rect [4 18 8 8], move [30 0], allowed [20 0]</title>`,
+	} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("SVG does not contain %s", want)
+		}
+	}
+	// Empty tiles have no element
+	if n := strings.Count(svg, "<title>"); n != 3 {
+		t.Errorf("got %d titled elements, want 3", n)
+	}
+	d := xml.NewDecoder(&buf)
+	for {
+		if _, err := d.Token(); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatalf("invalid XML: %v", err)
+		}
+	}
+}
+
+func TestWriteSVGFluids(t *testing.T) {
+	s := testScene()
+	s.Collider.TileMap[2][0] = 2
+	s.Collider.TileMap[2][1] = 3
+	s.Collider.Fluids = map[uint8]tilecollider.Fluid{2: {Buoyancy: 0.5}}
+	var buf bytes.Buffer
+	if err := WriteSVG(&buf, s); err != nil {
+		t.Fatal(err)
+	}
+	svg := buf.String()
+	if want := `<rect x="0" y="32" width="16" height="16" ` + svgPaint("fill", DefaultStyle.Fluid) + `><title>0,2: 2</title>`; !strings.Contains(svg, want) {
+		t.Errorf("SVG does not contain the fluid tile %s", want)
+	}
+	if !strings.Contains(svg, `<title>1,2: 3</title>`) {
+		t.Error("SVG does not contain the solid tile 1,2")
+	}
+
+	// The PNG renderer draws fluids the same way
+	if got := Render(s).RGBAAt(8, 40); got != rgba(DefaultStyle.Fluid) {
+		t.Errorf("rendered fluid tile = %v, want %v", got, rgba(DefaultStyle.Fluid))
+	}
+
+	// Without a fluid color only solid tiles are drawn
+	style := DefaultStyle
+	style.Fluid = nil
+	buf.Reset()
+	if err := WriteSVGStyle(&buf, s, style); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), `<title>0,2: 2</title>`) {
+		t.Error("fluid tile drawn without a fluid color")
+	}
+}
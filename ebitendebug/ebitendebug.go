@@ -0,0 +1,142 @@
+// Package ebitendebug draws a debug overlay of a tilecollider.Collider onto an Ebitengine image.
+package ebitendebug
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/vector"
+	"github.com/setanarut/tilecollider"
+)
+
+// Ray is a line segment to draw, e.g. a raycast of the game
+type Ray struct {
+	From [2]float64 // Start point in world pixels
+	To   [2]float64 // End point in world pixels
+	Hit  bool       // If true, the ray is drawn with the RayHit color
+}
+
+// Style is the color scheme of the overlay
+type Style struct {
+	Tile        color.Color // Solid tiles
+	Collided    color.Color // Tiles in Collider.Collisions
+	Normal      color.Color // Collision normals
+	Sensor      color.Color // Sensor regions
+	Ray         color.Color // Rays without hits
+	RayHit      color.Color // Rays with hits
+	StrokeWidth float32     // Width of lines in screen pixels
+}
+
+// DefaultStyle is the default color scheme
+var DefaultStyle = Style{
+	Tile:        color.RGBA{127, 127, 127, 80},
+	Collided:    color.RGBA{255, 255, 0, 200},
+	Normal:      color.RGBA{255, 0, 255, 255},
+	Sensor:      color.RGBA{0, 200, 255, 120},
+	Ray:         color.RGBA{0, 255, 0, 255},
+	RayHit:      color.RGBA{255, 60, 60, 255},
+	StrokeWidth: 1,
+}
+
+// Overlay draws the solid tiles and last collisions of a collider, plus sensor regions and rays set by the game
+type Overlay[T tilecollider.Integer] struct {
+	Collider *tilecollider.Collider[T]
+	Enabled  bool         // If false, Draw does nothing
+	Style    Style        // Color scheme
+	Sensors  [][4]float64 // X, Y, width and height of sensor regions in world pixels
+	Rays     []Ray        // Rays in world pixels
+}
+
+// New creates a new enabled overlay for the collider with DefaultStyle
+func New[T tilecollider.Integer](c *tilecollider.Collider[T]) *Overlay[T] {
+	return &Overlay[T]{Collider: c, Enabled: true, Style: DefaultStyle}
+}
+
+// Toggle enables or disables the overlay and returns the new state
+func (o *Overlay[T]) Toggle() bool {
+	o.Enabled = !o.Enabled
+	return o.Enabled
+}
+
+// Draw draws the overlay onto dst. camera transforms world pixels to screen pixels (e.g. from kamera.ApplyCameraTransform).
+// Only translation and scale of the camera are supported.
+func (o *Overlay[T]) Draw(dst *ebiten.Image, camera ebiten.GeoM) {
+	if !o.Enabled || o.Collider == nil {
+		return
+	}
+	c := o.Collider
+	tw, th := float64(c.TileSize[0]), float64(c.TileSize[1])
+
+	left, top, right, bottom := o.visibleTiles(dst.Bounds(), camera)
+	for y := top; y < bottom; y++ {
+		for x := left; x < right; x++ {
+			if id, ok := c.Tile(x, y); ok && c.IsSolid(id) {
+				o.fillRect(dst, camera, float64(x)*tw, float64(y)*th, tw, th, o.Style.Tile)
+			}
+		}
+	}
+
+	for _, s := range o.Sensors {
+		o.fillRect(dst, camera, s[0], s[1], s[2], s[3], o.Style.Sensor)
+	}
+
+	for _, col := range c.Collisions {
+		x, y := float64(col.TileCoords[0])*tw, float64(col.TileCoords[1])*th
+		o.strokeRect(dst, camera, x, y, tw, th, o.Style.Collided)
+		cx, cy := x+tw/2, y+th/2
+		o.arrow(dst, camera, cx, cy, cx+float64(col.Normal[0])*tw/2, cy+float64(col.Normal[1])*th/2, o.Style.Normal)
+	}
+
+	for _, r := range o.Rays {
+		clr := o.Style.Ray
+		if r.Hit {
+			clr = o.Style.RayHit
+		}
+		o.arrow(dst, camera, r.From[0], r.From[1], r.To[0], r.To[1], clr)
+	}
+}
+
+// visibleTiles returns the range of tiles visible in the screen rectangle. max values are exclusive.
+// The range is empty if the camera cannot be inverted.
+func (o *Overlay[T]) visibleTiles(screen image.Rectangle, camera ebiten.GeoM) (left, top, right, bottom int) {
+	inv := camera
+	if !inv.IsInvertible() {
+		return 0, 0, 0, 0
+	}
+	inv.Invert()
+	x0, y0 := inv.Apply(float64(screen.Min.X), float64(screen.Min.Y))
+	x1, y1 := inv.Apply(float64(screen.Max.X), float64(screen.Max.Y))
+	minX, minY, maxX, maxY := o.Collider.Bounds()
+	return tileRange(x0, y0, x1, y1, o.Collider.TileSize, minX, minY, maxX, maxY)
+}
+
+func (o *Overlay[T]) fillRect(dst *ebiten.Image, camera ebiten.GeoM, x, y, w, h float64, clr color.Color) {
+	x0, y0 := camera.Apply(x, y)
+	x1, y1 := camera.Apply(x+w, y+h)
+	vector.DrawFilledRect(dst, float32(x0), float32(y0), float32(x1-x0), float32(y1-y0), clr, false)
+}
+
+func (o *Overlay[T]) strokeRect(dst *ebiten.Image, camera ebiten.GeoM, x, y, w, h float64, clr color.Color) {
+	x0, y0 := camera.Apply(x, y)
+	x1, y1 := camera.Apply(x+w, y+h)
+	vector.StrokeRect(dst, float32(x0), float32(y0), float32(x1-x0), float32(y1-y0), o.Style.StrokeWidth, clr, false)
+}
+
+// arrow draws a line with a small head at the end
+func (o *Overlay[T]) arrow(dst *ebiten.Image, camera ebiten.GeoM, x0, y0, x1, y1 float64, clr color.Color) {
+	ax, ay := camera.Apply(x0, y0)
+	bx, by := camera.Apply(x1, y1)
+	sw := o.Style.StrokeWidth
+	vector.StrokeLine(dst, float32(ax), float32(ay), float32(bx), float32(by), sw, clr, true)
+	dx, dy := bx-ax, by-ay
+	length := math.Hypot(dx, dy)
+	if length < 1 {
+		return
+	}
+	head := min(6, length/2)
+	ux, uy := dx/length*head, dy/length*head
+	vector.StrokeLine(dst, float32(bx), float32(by), float32(bx-ux+uy), float32(by-uy-ux), sw, clr, true)
+	vector.StrokeLine(dst, float32(bx), float32(by), float32(bx-ux-uy), float32(by-uy+ux), sw, clr, true)
+}
@@ -0,0 +1,35 @@
+package ebitendebug
+
+import "math"
+
+// tileRange returns the tiles overlapped by the world rectangle from x0,y0 to x1,y1, clipped to the tilemap bounds.
+// The corners can be in any order. max values are exclusive. The range is empty if a corner is not finite.
+func tileRange(x0, y0, x1, y1 float64, tileSize [2]int, minX, minY, maxX, maxY int) (left, top, right, bottom int) {
+	tw, th := float64(tileSize[0]), float64(tileSize[1])
+	l, t := math.Floor(min(x0, x1)/tw), math.Floor(min(y0, y1)/th)
+	r, b := math.Ceil(max(x0, x1)/tw), math.Ceil(max(y0, y1)/th)
+	if !finite(l) || !finite(t) || !finite(r) || !finite(b) {
+		return 0, 0, 0, 0
+	}
+	left, top = clamp(l, minX, maxX), clamp(t, minY, maxY)
+	right, bottom = clamp(r, minX, maxX), clamp(b, minY, maxY)
+	if left >= right || top >= bottom {
+		return 0, 0, 0, 0
+	}
+	return left, top, right, bottom
+}
+
+// clamp converts v to an int in the range lo..hi. It avoids the undefined conversion of floats outside the int range.
+func clamp(v float64, lo, hi int) int {
+	if v <= float64(lo) {
+		return lo
+	}
+	if v >= float64(hi) {
+		return hi
+	}
+	return int(v)
+}
+
+func finite(v float64) bool {
+	return !math.IsInf(v, 0) && !math.IsNaN(v)
+}
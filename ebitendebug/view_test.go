@@ -0,0 +1,59 @@
+package ebitendebug
+
+import (
+	"image"
+	"math"
+	"testing"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/setanarut/tilecollider"
+)
+
+func TestTileRange(t *testing.T) {
+	unbounded := [4]int{math.MinInt, math.MinInt, math.MaxInt, math.MaxInt}
+	tests := []struct {
+		name           string
+		x0, y0, x1, y1 float64
+		bounds         [4]int
+		want           [4]int
+	}{
+		{"inside", 16, 8, 48, 40, [4]int{0, 0, 10, 10}, [4]int{1, 0, 3, 4}},
+		{"partial tiles", 15, 7, 49, 41, [4]int{0, 0, 10, 10}, [4]int{0, 0, 4, 4}},
+		{"swapped corners", 48, 40, 16, 8, [4]int{0, 0, 10, 10}, [4]int{1, 0, 3, 4}},
+		{"clipped", -100, -100, 1000, 1000, [4]int{0, 0, 10, 10}, [4]int{0, 0, 10, 10}},
+		{"outside", 500, 500, 600, 600, [4]int{0, 0, 10, 10}, [4]int{}},
+		{"negative", -40, -20, -8, 0, unbounded, [4]int{-3, -2, 0, 0}},
+		{"huge unbounded", -1e300, -1e300, 1e300, 1e300, unbounded, unbounded},
+		{"infinite", math.Inf(-1), 0, 16, 16, unbounded, [4]int{}},
+		{"nan", math.NaN(), 0, 16, 16, [4]int{0, 0, 10, 10}, [4]int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.bounds
+			left, top, right, bottom := tileRange(tt.x0, tt.y0, tt.x1, tt.y1, [2]int{16, 12}, b[0], b[1], b[2], b[3])
+			if got := [4]int{left, top, right, bottom}; got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleTiles(t *testing.T) {
+	chunks := tilecollider.NewChunkMap[uint8](8, 8, 0, nil)
+	o := New(tilecollider.NewColliderSource[uint8](chunks, 16, 16))
+	screen := image.Rect(0, 0, 320, 240)
+
+	var camera ebiten.GeoM
+	camera.Translate(-100, -50)
+	camera.Scale(2, 2)
+	left, top, right, bottom := o.visibleTiles(screen, camera)
+	if got, want := [4]int{left, top, right, bottom}, [4]int{6, 3, 17, 11}; got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	// A camera that cannot be inverted shows nothing, even on an unbounded map
+	camera.Scale(0, 1)
+	if left, top, right, bottom := o.visibleTiles(screen, camera); left != right || top != bottom {
+		t.Errorf("singular camera shows tiles %d,%d to %d,%d", left, top, right, bottom)
+	}
+}
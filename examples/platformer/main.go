@@ -11,6 +11,7 @@ import (
 	"github.com/hajimehoshi/ebiten/v2/vector"
 	"github.com/setanarut/kamera/v2"
 	"github.com/setanarut/tilecollider"
+	"github.com/setanarut/tilecollider/ebitendebug"
 )
 
 func main() {
@@ -34,6 +35,7 @@ var TileMap = [][]uint8{
 func init() {
 	Controller.SetPhyicsScale(2.2)
 	cam.LerpEnabled = true
+	overlay.Style.Tile = color.Gray{127}
 }
 
 var infoText string
@@ -54,6 +56,7 @@ var (
 
 var Controller = NewPlayerController()
 var collider = tilecollider.NewCollider(TileMap, TileSize[0], TileSize[1])
+var overlay = ebitendebug.New(collider)
 
 func (g *Game) Update() error {
 	if Vel[1] < 0 {
@@ -95,51 +98,12 @@ func (g *Game) Layout(w, h int) (int, int) {
 type Game struct{}
 
 func (g *Game) Draw(s *ebiten.Image) {
-
-	for y, row := range TileMap {
-		for x, value := range row {
-			if value != 0 {
-				px, py := float64(x*TileSize[0]), float64(y*TileSize[1])
-				geom := &ebiten.GeoM{}
-				cam.ApplyCameraTransform(geom)
-				px, py = geom.Apply(px, py)
-				vector.DrawFilledRect(
-					s,
-					float32(px),
-					float32(py),
-					float32(TileSize[0]),
-					float32(TileSize[1]),
-					color.Gray{127},
-					false,
-				)
-			}
-		}
-	}
-
-	// draw collided tiles
-	for _, col := range collider.Collisions {
-
-		px, py := float64(col.TileCoords[0]*collider.TileSize[0]), float64(col.TileCoords[1]*collider.TileSize[1])
-		geom := &ebiten.GeoM{}
-		cam.ApplyCameraTransform(geom)
-		px, py = geom.Apply(px, py)
-
-		vector.DrawFilledRect(
-			s,
-			float32(px),
-			float32(py),
-			float32(collider.TileSize[0]),
-			float32(collider.TileSize[1]),
-			color.RGBA{R: 255, G: 255, B: 0, A: 30},
-			false,
-		)
-	}
-
-	// draw player
-	x, y := Box[0], Box[1]
 	geom := &ebiten.GeoM{}
 	cam.ApplyCameraTransform(geom)
-	x, y = geom.Apply(x, y)
+	overlay.Draw(s, *geom)
+
+	// draw player
+	x, y := geom.Apply(Box[0], Box[1])
 	vector.DrawFilledRect(
 		s,
 		float32(x),
@@ -0,0 +1,352 @@
+package tilecollider
+
+import (
+	"math"
+	"math/bits"
+)
+
+// FixedShift is the number of fractional bits of Fixed
+const FixedShift = 16
+
+// Fixed is a signed 48.16 fixed-point number used by FixedCollider for deterministic results on every machine
+type Fixed int64
+
+// FixedOne is the Fixed value of 1
+const FixedOne Fixed = 1 << FixedShift
+
+// FixedFromInt converts an integer to Fixed
+func FixedFromInt(i int) Fixed {
+	return Fixed(i) << FixedShift
+}
+
+// FixedFromFloat converts a float to the nearest Fixed value
+func FixedFromFloat(f float64) Fixed {
+	return Fixed(math.Round(f * float64(FixedOne)))
+}
+
+// Float converts the value to float64
+func (f Fixed) Float() float64 {
+	return float64(f) / float64(FixedOne)
+}
+
+// Floor returns the greatest integer less than or equal to the value
+func (f Fixed) Floor() int {
+	return int(f >> FixedShift)
+}
+
+// Mul returns the product of two values, rounded toward negative infinity.
+// The intermediate product is computed in 128 bits, so the result is exact whenever it fits in Fixed;
+// otherwise it wraps around.
+func (f Fixed) Mul(g Fixed) Fixed {
+	hi, lo := bits.Mul64(uint64(f), uint64(g))
+	// Correct the unsigned high word to the signed product
+	if f < 0 {
+		hi -= uint64(g)
+	}
+	if g < 0 {
+		hi -= uint64(f)
+	}
+	return Fixed(lo>>FixedShift | hi<<(64-FixedShift))
+}
+
+// Div returns the quotient of two values, rounded toward negative infinity.
+// The shifted dividend is computed in 128 bits, so the result is exact whenever it fits in Fixed.
+// It panics if g is zero or the quotient does not fit in Fixed.
+func (f Fixed) Div(g Fixed) Fixed {
+	a, b := uint64(fixedAbs(f)), uint64(fixedAbs(g))
+	if b == 0 {
+		panic("tilecollider: Fixed division by zero")
+	}
+	hi, lo := a>>(64-FixedShift), a<<FixedShift
+	if hi >= b {
+		panic("tilecollider: Fixed division overflow")
+	}
+	q, r := bits.Div64(hi, lo, b)
+	if (f < 0) == (g < 0) {
+		if q > math.MaxInt64 {
+			panic("tilecollider: Fixed division overflow")
+		}
+		return Fixed(q)
+	}
+	if r != 0 {
+		q++
+	}
+	if q > 1<<63 {
+		panic("tilecollider: Fixed division overflow")
+	}
+	return Fixed(-q)
+}
+
+// FixedCollisionCallback is called when collisions occur, receiving collision info and final movement
+type FixedCollisionCallback[T Integer] func([]CollisionInfo[T], Fixed, Fixed)
+
+// FixedCollider is a variant of Collider that works in Fixed arithmetic.
+// It shares the tiles and settings of the embedded Collider and produces the same results as the
+// float64 Collider for inputs that are exactly representable in both.
+type FixedCollider[T Integer] struct {
+	*Collider[T]
+}
+
+// NewFixedCollider creates a new fixed-point tile collider with the given tilemap and tile dimensions
+func NewFixedCollider[T Integer](tileMap [][]T, tileWidth, tileHeight int) *FixedCollider[T] {
+	return &FixedCollider[T]{NewCollider(tileMap, tileWidth, tileHeight)}
+}
+
+// Collide checks for collisions when moving a rectangle and returns the allowed movement
+func (c *FixedCollider[T]) Collide(rectX, rectY, rectW, rectH, moveX, moveY Fixed, onCollide FixedCollisionCallback[T]) (Fixed, Fixed) {
+
+	c.Collisions = c.Collisions[:0]
+
+	if moveX == 0 && moveY == 0 {
+		if !c.StaticCheck {
+			return moveX, moveY
+		}
+		// Static collision test
+		tileW, tileH := FixedFromInt(c.TileSize[0]), FixedFromInt(c.TileSize[1])
+		minX, minY, maxX, maxY := c.bounds()
+		playerLeft, playerTop, playerRight, playerBottom := c.fixedCellRange(rectX, rectY, rectW, rectH)
+
+		minPenetration := Fixed(math.MaxInt64)
+		var resolveX, resolveY Fixed
+
+		for y := playerTop; y <= playerBottom; y++ {
+			if y < minY || y >= maxY {
+				continue
+			}
+			for x := playerLeft; x <= playerRight; x++ {
+				if x < minX || x >= maxX {
+					continue
+				}
+				if c.IsSolid(c.tile(x, y)) {
+					// Calculate overlap on each axis
+					tileLeft := FixedFromInt(x * c.TileSize[0])
+					tileTop := FixedFromInt(y * c.TileSize[1])
+
+					overlapX := min(rectX+rectW-tileLeft, tileLeft+tileW-rectX)
+					overlapY := min(rectY+rectH-tileTop, tileTop+tileH-rectY)
+
+					// Choose smallest penetration. Centers are compared doubled to stay exact.
+					if overlapX < overlapY && overlapX < minPenetration {
+						minPenetration = overlapX
+						resolveY = 0
+						if 2*rectX+rectW < 2*tileLeft+tileW {
+							resolveX = -overlapX
+						} else {
+							resolveX = overlapX
+						}
+					} else if overlapY < minPenetration {
+						minPenetration = overlapY
+						resolveX = 0
+						if 2*rectY+rectH < 2*tileTop+tileH {
+							resolveY = -overlapY
+						} else {
+							resolveY = overlapY
+						}
+					}
+
+					c.Collisions = append(c.Collisions, CollisionInfo[T]{
+						TileID:     c.tile(x, y),
+						TileCoords: [2]int{x, y},
+						Normal:     [2]int{fixedNormal(resolveX), fixedNormal(resolveY)},
+					})
+				}
+			}
+		}
+		return resolveX, resolveY
+	}
+
+	if fixedAbs(moveX) > fixedAbs(moveY) {
+		if moveX != 0 {
+			moveX = c.CollideX(rectX, rectY, rectW, rectH, moveX)
+		}
+		if moveY != 0 {
+			moveY = c.CollideY(rectX+moveX, rectY, rectW, rectH, moveY)
+		}
+	} else {
+		if moveY != 0 {
+			moveY = c.CollideY(rectX, rectY, rectW, rectH, moveY)
+		}
+		if moveX != 0 {
+			moveX = c.CollideX(rectX, rectY+moveY, rectW, rectH, moveX)
+		}
+	}
+
+	if onCollide != nil {
+		onCollide(c.Collisions, moveX, moveY)
+	}
+
+	return moveX, moveY
+}
+
+// CollideX checks for collisions along the X axis and returns the allowed X movement
+func (c *FixedCollider[T]) CollideX(rectX, rectY, rectW, rectH, moveX Fixed) Fixed {
+
+	tileW, tileH := int64(FixedFromInt(c.TileSize[0])), int64(FixedFromInt(c.TileSize[1]))
+	minX, minY, maxX, maxY := c.bounds()
+	checkLimit := max(1, int(ceilDiv(int64(fixedAbs(moveX)), tileW))+1)
+
+	playerTop := int(floorDiv(int64(rectY), tileH))
+	playerBottom := int(ceilDiv(int64(rectY+rectH), tileH)) - 1
+
+	if moveX > 0 {
+		startX := int(floorDiv(int64(rectX+rectW), tileW))
+		endX := min(startX+checkLimit, maxX)
+
+		for y := playerTop; y <= playerBottom; y++ {
+			if y < minY || y >= maxY {
+				continue
+			}
+			for x := startX; x < endX; x++ {
+				if x < minX || x >= maxX {
+					continue
+				}
+				if c.IsSolid(c.tile(x, y)) {
+					collision := FixedFromInt(x*c.TileSize[0]) - (rectX + rectW)
+					if collision <= moveX {
+						moveX = collision
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.tile(x, y),
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{-1, 0},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if moveX < 0 {
+		endX := int(floorDiv(int64(rectX), tileW))
+		startX := max(endX-checkLimit, minX)
+
+		for y := playerTop; y <= playerBottom; y++ {
+			if y < minY || y >= maxY {
+				continue
+			}
+			for x := startX; x <= endX; x++ {
+				if x < minX || x >= maxX {
+					continue
+				}
+				if c.IsSolid(c.tile(x, y)) {
+					collision := FixedFromInt((x+1)*c.TileSize[0]) - rectX
+					if collision >= moveX {
+						moveX = collision
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.tile(x, y),
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{1, 0},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return moveX
+}
+
+// CollideY checks for collisions along the Y axis and returns the allowed Y movement
+func (c *FixedCollider[T]) CollideY(rectX, rectY, rectW, rectH, moveY Fixed) Fixed {
+
+	tileW, tileH := int64(FixedFromInt(c.TileSize[0])), int64(FixedFromInt(c.TileSize[1]))
+	minX, minY, maxX, maxY := c.bounds()
+	checkLimit := max(1, int(ceilDiv(int64(fixedAbs(moveY)), tileH))+1)
+
+	playerLeft := int(floorDiv(int64(rectX), tileW))
+	playerRight := int(ceilDiv(int64(rectX+rectW), tileW)) - 1
+
+	if moveY > 0 {
+		startY := int(floorDiv(int64(rectY+rectH), tileH))
+		endY := min(startY+checkLimit, maxY)
+
+		for x := playerLeft; x <= playerRight; x++ {
+			if x < minX || x >= maxX {
+				continue
+			}
+			for y := startY; y < endY; y++ {
+				if y < minY || y >= maxY {
+					continue
+				}
+				if c.IsSolid(c.tile(x, y)) {
+					collision := FixedFromInt(y*c.TileSize[1]) - (rectY + rectH)
+					if collision <= moveY {
+						moveY = collision
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.tile(x, y),
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{0, -1},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if moveY < 0 {
+		endY := int(floorDiv(int64(rectY), tileH))
+		startY := max(endY-checkLimit, minY)
+
+		for x := playerLeft; x <= playerRight; x++ {
+			if x < minX || x >= maxX {
+				continue
+			}
+			for y := startY; y <= endY; y++ {
+				if y < minY || y >= maxY {
+					continue
+				}
+				if c.IsSolid(c.tile(x, y)) {
+					collision := FixedFromInt((y+1)*c.TileSize[1]) - rectY
+					if collision >= moveY {
+						moveY = collision
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.tile(x, y),
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{0, 1},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return moveY
+}
+
+// fixedCellRange returns the inclusive range of tile coordinates overlapped by the rectangle
+func (c *FixedCollider[T]) fixedCellRange(rectX, rectY, rectW, rectH Fixed) (left, top, right, bottom int) {
+	tileW, tileH := int64(FixedFromInt(c.TileSize[0])), int64(FixedFromInt(c.TileSize[1]))
+	left = int(floorDiv(int64(rectX), tileW))
+	top = int(floorDiv(int64(rectY), tileH))
+	right = int(ceilDiv(int64(rectX+rectW), tileW)) - 1
+	bottom = int(ceilDiv(int64(rectY+rectH), tileH)) - 1
+	return
+}
+
+// fixedNormal returns the normal component opposite to the resolve direction, like math.Copysign(1, -resolve)
+func fixedNormal(resolve Fixed) int {
+	if resolve < 0 {
+		return 1
+	}
+	return -1
+}
+
+func fixedAbs(f Fixed) Fixed {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// floorDiv returns a/b rounded toward negative infinity
+func floorDiv(a, b int64) int64 {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
+
+// ceilDiv returns a/b rounded toward positive infinity
+func ceilDiv(a, b int64) int64 {
+	return -floorDiv(-a, b)
+}
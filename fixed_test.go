@@ -0,0 +1,152 @@
+package tilecollider
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// checkFixedEquivalent compares FixedCollider with the float64 Collider on inputs that are exact in both
+func checkFixedEquivalent(t *testing.T, iterations int, origin float64, static bool) {
+	t.Helper()
+	r := rand.New(rand.NewSource(9))
+	// Quarter steps are exactly representable in Fixed and float64
+	q := func(n int) float64 { return float64(r.Intn(n*4)-n*2) / 4 }
+	for i := range iterations {
+		m := randMap(r, 3+r.Intn(10), 3+r.Intn(10))
+		tw, th := 8+r.Intn(20), 8+r.Intn(20)
+		c := NewCollider(m, tw, th)
+		f := NewFixedCollider(m, tw, th)
+		c.StaticCheck, f.StaticCheck = static, static
+
+		x, y := origin+q(150)+50, origin+q(150)+50
+		w, h := float64(1+r.Intn(40))+float64(r.Intn(2))/2, float64(1+r.Intn(40))
+		mx, my := q(20), q(20)
+		if static {
+			mx, my = 0, 0
+		}
+		F := FixedFromFloat
+		cx, cy := c.Collide(x, y, w, h, mx, my, nil)
+		fx, fy := f.Collide(F(x), F(y), F(w), F(h), F(mx), F(my), nil)
+		if fx.Float() != cx || fy.Float() != cy {
+			t.Fatalf("iteration %d: Collide(%v, %v, %v, %v, %v, %v) = %v, %v, want %v, %v",
+				i, x, y, w, h, mx, my, fx.Float(), fy.Float(), cx, cy)
+		}
+		if len(f.Collisions) != len(c.Collisions) {
+			t.Fatalf("iteration %d: %d collisions, want %d", i, len(f.Collisions), len(c.Collisions))
+		}
+		for j := range c.Collisions {
+			if f.Collisions[j] != c.Collisions[j] {
+				t.Fatalf("iteration %d: collision %d = %v, want %v", i, j, f.Collisions[j], c.Collisions[j])
+			}
+		}
+	}
+}
+
+func TestFixedMatchesFloat(t *testing.T) {
+	t.Run("moving", func(t *testing.T) { checkFixedEquivalent(t, 20000, 0, false) })
+	t.Run("static", func(t *testing.T) { checkFixedEquivalent(t, 20000, 0, true) })
+	// Rectangles partly or fully outside the map at negative coordinates
+	t.Run("negative", func(t *testing.T) { checkFixedEquivalent(t, 20000, -150, false) })
+	t.Run("negative static", func(t *testing.T) { checkFixedEquivalent(t, 20000, -150, true) })
+}
+
+func TestFixedConversions(t *testing.T) {
+	tests := []struct {
+		f     float64
+		floor int
+	}{
+		{0, 0}, {1, 1}, {1.5, 1}, {-0.25, -1}, {-1, -1}, {-1.5, -2}, {1 << 40, 1 << 40},
+	}
+	for _, tt := range tests {
+		v := FixedFromFloat(tt.f)
+		if v.Float() != tt.f {
+			t.Errorf("FixedFromFloat(%v).Float() = %v", tt.f, v.Float())
+		}
+		if v.Floor() != tt.floor {
+			t.Errorf("FixedFromFloat(%v).Floor() = %v, want %v", tt.f, v.Floor(), tt.floor)
+		}
+	}
+	if got := FixedFromInt(-3); got != -3*FixedOne {
+		t.Errorf("FixedFromInt(-3) = %v", got)
+	}
+}
+
+// floorBig returns floor(n / d) if it fits in Fixed
+func floorBig(n, d *big.Int) (Fixed, bool) {
+	q, m := new(big.Int).DivMod(n, d, new(big.Int))
+	// DivMod rounds toward negative infinity only for positive d
+	if d.Sign() < 0 && m.Sign() != 0 {
+		q.Sub(q, big.NewInt(1))
+	}
+	if !q.IsInt64() {
+		return 0, false
+	}
+	return Fixed(q.Int64()), true
+}
+
+func TestFixedMulDiv(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	one := big.NewInt(int64(FixedOne))
+	values := []Fixed{0, 1, -1, FixedOne, -FixedOne, math.MaxInt64, math.MinInt64 + 1, 1 << 40, -1 << 40}
+	for range 2000 {
+		// Random magnitudes up to 2^62
+		values = append(values, Fixed(r.Int63()>>r.Intn(63))*Fixed(1-2*r.Intn(2)))
+	}
+	for _, a := range values {
+		for _, b := range values[:100] {
+			A, B := big.NewInt(int64(a)), big.NewInt(int64(b))
+			if want, ok := floorBig(new(big.Int).Mul(A, B), one); ok {
+				if got := a.Mul(b); got != want {
+					t.Fatalf("%d.Mul(%d) = %d, want %d", a, b, got, want)
+				}
+			}
+			if b == 0 {
+				continue
+			}
+			want, ok := floorBig(new(big.Int).Mul(A, one), B)
+			if !ok {
+				continue
+			}
+			if got := a.Div(b); got != want {
+				t.Fatalf("%d.Div(%d) = %d, want %d", a, b, got, want)
+			}
+		}
+	}
+}
+
+func TestFixedLargeValues(t *testing.T) {
+	// These overflowed with 64-bit intermediates
+	a, b := FixedFromInt(1<<20), FixedFromInt(1<<20)
+	if got, want := a.Mul(b), FixedFromInt(1<<40); got != want {
+		t.Errorf("Mul = %v, want %v", got.Float(), want.Float())
+	}
+	if got, want := FixedFromInt(1<<40).Div(FixedFromInt(1<<20)), FixedFromInt(1<<20); got != want {
+		t.Errorf("Div = %v, want %v", got.Float(), want.Float())
+	}
+	if got, want := FixedFromFloat(-3).Div(FixedFromInt(2)), FixedFromFloat(-1.5); got != want {
+		t.Errorf("Div = %v, want %v", got.Float(), want.Float())
+	}
+}
+
+func TestFixedDivPanics(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Fixed
+	}{
+		{"zero", FixedOne, 0},
+		{"overflow", math.MaxInt64, 1},
+		{"min overflow", math.MinInt64, FixedOne / 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%d.Div(%d) did not panic", tt.a, tt.b)
+				}
+			}()
+			tt.a.Div(tt.b)
+		})
+	}
+}
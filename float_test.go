@@ -0,0 +1,62 @@
+package tilecollider
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestCollideFloat32(t *testing.T) {
+	// Quarter steps below 2^16 are exact in float32, so results must match float64 exactly
+	r := rand.New(rand.NewSource(4))
+	q := func(v float64) float64 { return math.Round(v*4) / 4 }
+	for i := range 20000 {
+		m := randMap(r, 3+r.Intn(10), 3+r.Intn(10))
+		tw, th := 8+r.Intn(20), 8+r.Intn(20)
+		c := NewCollider(m, tw, th)
+		c32 := NewColliderOf[uint8, float32](m, tw, th)
+		tc := randCase(r)
+		c.StaticCheck, c32.StaticCheck = tc.static, tc.static
+		x, y, w, h, mx, my := q(tc.x), q(tc.y), max(q(tc.w), 1), max(q(tc.h), 1), q(tc.moveX), q(tc.moveY)
+
+		wantX, wantY := c.Collide(x, y, w, h, mx, my, nil)
+		gotX, gotY := c32.Collide(float32(x), float32(y), float32(w), float32(h), float32(mx), float32(my), nil)
+		if float64(gotX) != wantX || float64(gotY) != wantY {
+			t.Fatalf("case %d: Collide(%v, %v, %v, %v, %v, %v) = %v,%v want %v,%v", i, x, y, w, h, mx, my, gotX, gotY, wantX, wantY)
+		}
+		if !slices.Equal(c32.Collisions, c.Collisions) {
+			t.Fatalf("case %d: got collisions %v want %v", i, c32.Collisions, c.Collisions)
+		}
+	}
+}
+
+func TestCollideFloat32WallContact(t *testing.T) {
+	// Far from the origin float32 cannot represent the fractional part of the position.
+	// A rectangle moved into a wall must still end flush against it, not inside it.
+	r := rand.New(rand.NewSource(5))
+	m := make([][]uint8, 3)
+	for y := range m {
+		m[y] = make([]uint8, 1<<20)
+	}
+	c := NewColliderOf[uint8, float32](m, 16, 16)
+	for i := range 2000 {
+		wall := 1<<19 + r.Intn(1<<19)
+		for y := range m {
+			m[y][wall] = 1
+		}
+		x := float32(wall*16) - 20 - r.Float32()*30
+		c.StaticCheck = false
+		dx, _ := c.Collide(x, 10, 10, 10, 40, 0, nil)
+		if x+dx+10 > float32(wall*16) {
+			t.Fatalf("case %d: moved to %v, past the wall at %v", i, x+dx+10, wall*16)
+		}
+		c.StaticCheck = true
+		if sx, sy := c.Collide(x+dx, 10, 10, 10, 0, 0, nil); sx != 0 || sy != 0 {
+			t.Fatalf("case %d: rectangle at %v overlaps the wall, resolve %v,%v", i, x+dx, sx, sy)
+		}
+		for y := range m {
+			m[y][wall] = 0
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package tilecollider
+
+import "math"
+
+// Fluid describes a non-solid tile type that applies buoyancy and drag to overlapping rectangles
+type Fluid struct {
+	Buoyancy float64 // Upward acceleration applied at full submersion
+	Drag     float64 // Fraction of velocity removed per step at full submersion (0-1)
+}
+
+// FluidInfo stores information about the fluid overlapping a rectangle
+type FluidInfo[T Integer] struct {
+	TileID    T       // ID of the fluid tile with the largest overlap
+	Submerged float64 // Fraction of the rectangle area inside fluid tiles (0-1)
+}
+
+// SurfaceCallback is called when a rectangle enters (entered is true) or leaves a fluid volume
+type SurfaceCallback[T Integer] func(tileID T, entered bool)
+
+// Submerged returns the fraction of the rectangle area covered by fluid tiles
+func (c *ColliderOf[T, F]) Submerged(rectX, rectY, rectW, rectH F) FluidInfo[T] {
+	var info FluidInfo[T]
+	if len(c.Fluids) == 0 || rectW <= 0 || rectH <= 0 {
+		return info
+	}
+
+	minX, minY, maxX, maxY := c.bounds()
+	left, top, right, bottom := c.cellRange(rectX, rectY, rectW, rectH)
+	rectLeft, rectTop := float64(rectX), float64(rectY)
+	rectRight, rectBottom := float64(rectX+rectW), float64(rectY+rectH)
+	// Overlap area per fluid ID. Rectangles rarely touch more than a few fluids, so a slice backed by the stack avoids allocating.
+	var buf [4]fluidArea[T]
+	areas := buf[:0]
+	var total float64
+
+	for y := top; y <= bottom; y++ {
+		if y < minY || y >= maxY {
+			continue
+		}
+		for x := left; x <= right; x++ {
+			if x < minX || x >= maxX {
+				continue
+			}
+			id := c.tile(x, y)
+			if _, ok := c.Fluids[id]; !ok {
+				continue
+			}
+			tileLeft := float64(x * c.TileSize[0])
+			tileTop := float64(y * c.TileSize[1])
+			overlapX := min(rectRight, tileLeft+float64(c.TileSize[0])) - max(rectLeft, tileLeft)
+			overlapY := min(rectBottom, tileTop+float64(c.TileSize[1])) - max(rectTop, tileTop)
+			if overlapX <= 0 || overlapY <= 0 {
+				continue
+			}
+			areas = addFluidArea(areas, id, overlapX*overlapY)
+			total += overlapX * overlapY
+		}
+	}
+
+	largest := 0.0
+	for _, a := range areas {
+		if a.area > largest || (a.area == largest && a.id < info.TileID) {
+			largest = a.area
+			info.TileID = a.id
+		}
+	}
+	info.Submerged = math.Min(1, total/((rectRight-rectLeft)*(rectBottom-rectTop)))
+	return info
+}
+
+// ApplyFluid applies drag and buoyancy of the overlapped fluid to the velocity and returns the new velocity.
+//
+// last holds the result of the previous call for the same rectangle and is updated in place.
+// onSurface is called when the rectangle enters or leaves a fluid volume. Both may be nil.
+func (c *ColliderOf[T, F]) ApplyFluid(rectX, rectY, rectW, rectH, velX, velY F, last *FluidInfo[T], onSurface SurfaceCallback[T]) (F, F) {
+	info := c.Submerged(rectX, rectY, rectW, rectH)
+
+	if last != nil {
+		if onSurface != nil {
+			wasIn, isIn := last.Submerged > 0, info.Submerged > 0
+			// Moving straight from one fluid into another leaves the first and enters the second
+			if wasIn && (!isIn || last.TileID != info.TileID) {
+				onSurface(last.TileID, false)
+			}
+			if isIn && (!wasIn || last.TileID != info.TileID) {
+				onSurface(info.TileID, true)
+			}
+		}
+		*last = info
+	}
+
+	if info.Submerged == 0 {
+		return velX, velY
+	}
+
+	fluid := c.Fluids[info.TileID]
+	damping := 1 - math.Min(1, fluid.Drag*info.Submerged)
+	vx := float64(velX) * damping
+	vy := float64(velY)*damping - fluid.Buoyancy*info.Submerged
+	return F(vx), F(vy)
+}
+
+// fluidArea is the overlap area of a fluid tile ID
+type fluidArea[T Integer] struct {
+	id   T
+	area float64
+}
+
+// addFluidArea adds the area to the entry of the fluid ID
+func addFluidArea[T Integer](areas []fluidArea[T], id T, area float64) []fluidArea[T] {
+	for i := range areas {
+		if areas[i].id == id {
+			areas[i].area += area
+			return areas
+		}
+	}
+	return append(areas, fluidArea[T]{id, area})
+}
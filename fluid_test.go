@@ -0,0 +1,99 @@
+package tilecollider
+
+import (
+	"math"
+	"testing"
+)
+
+// fluidMap has water (2) on the left and lava (3) on the right, below an empty row
+var fluidMap = [][]int{
+	{0, 0, 0, 0},
+	{2, 2, 3, 3},
+	{2, 2, 3, 3},
+}
+
+func newFluidCollider() *Collider[int] {
+	c := NewCollider(fluidMap, 16, 16)
+	c.Fluids = map[int]Fluid{
+		2: {Buoyancy: 0.5, Drag: 0.2},
+		3: {Buoyancy: 1, Drag: 0.8},
+	}
+	return c
+}
+
+func TestSubmerged(t *testing.T) {
+	c := newFluidCollider()
+	tests := []struct {
+		name       string
+		x, y, w, h float64
+		want       FluidInfo[int]
+	}{
+		{"outside", 0, 0, 16, 16, FluidInfo[int]{}},
+		{"half in water", 0, 8, 16, 16, FluidInfo[int]{TileID: 2, Submerged: 0.5}},
+		{"fully in lava", 40, 20, 8, 8, FluidInfo[int]{TileID: 3, Submerged: 1}},
+		{"mostly lava", 28, 16, 16, 16, FluidInfo[int]{TileID: 3, Submerged: 1}},
+		{"tie picks smaller ID", 24, 16, 16, 16, FluidInfo[int]{TileID: 2, Submerged: 1}},
+	}
+	for _, tt := range tests {
+		got := c.Submerged(tt.x, tt.y, tt.w, tt.h)
+		if got.TileID != tt.want.TileID || math.Abs(got.Submerged-tt.want.Submerged) > 1e-12 {
+			t.Errorf("%s: got %+v want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSubmergedAllocs(t *testing.T) {
+	c := newFluidCollider()
+	allocs := testing.AllocsPerRun(100, func() {
+		c.Submerged(10, 10, 40, 30)
+	})
+	if allocs != 0 {
+		t.Errorf("Submerged allocates %v times per call", allocs)
+	}
+}
+
+func TestApplyFluidSurfaceEvents(t *testing.T) {
+	c := newFluidCollider()
+	type event struct {
+		id      int
+		entered bool
+	}
+	var events []event
+	onSurface := func(id int, entered bool) { events = append(events, event{id, entered}) }
+
+	var last FluidInfo[int]
+	steps := []struct {
+		x, y float64
+		want []event
+	}{
+		{0, 0, nil},                              // in the air
+		{0, 20, []event{{2, true}}},              // into water
+		{4, 20, nil},                             // still in water
+		{44, 20, []event{{2, false}, {3, true}}}, // straight into lava
+		{44, 0, []event{{3, false}}},             // out of lava
+	}
+	for i, s := range steps {
+		events = nil
+		c.ApplyFluid(s.x, s.y, 8, 8, 0, 0, &last, onSurface)
+		if len(events) != len(s.want) {
+			t.Fatalf("step %d: got events %v want %v", i, events, s.want)
+		}
+		for j := range events {
+			if events[j] != s.want[j] {
+				t.Fatalf("step %d: got events %v want %v", i, events, s.want)
+			}
+		}
+	}
+}
+
+func TestApplyFluidVelocity(t *testing.T) {
+	c := newFluidCollider()
+	vx, vy := c.ApplyFluid(40, 20, 8, 8, 10, 10, nil, nil)
+	if math.Abs(vx-2) > 1e-12 || math.Abs(vy-1) > 1e-12 {
+		t.Errorf("got %v,%v want 2,1", vx, vy)
+	}
+	vx, vy = c.ApplyFluid(0, 0, 8, 8, 10, 10, nil, nil)
+	if vx != 10 || vy != 10 {
+		t.Errorf("got %v,%v outside fluids", vx, vy)
+	}
+}
@@ -1,6 +1,6 @@
 module github.com/setanarut/tilecollider
 
-go 1.23.2
+go 1.24
 
 retract [v1.0.0, v1.4.1] // several bugs
 
@@ -0,0 +1,57 @@
+package tilecollider
+
+// Grid is a flat, row-major tile storage. It avoids loading a row header for every tile read from [][]T,
+// which speeds up long sweeps across many rows of large maps.
+type Grid[T Integer] struct {
+	Data   []T // Tile IDs in row-major order
+	Width  int // Number of tiles in a row
+	Height int // Number of rows
+	Stride int // Distance between the starts of two consecutive rows in Data
+}
+
+// NewGrid creates a new empty grid with the given size
+func NewGrid[T Integer](width, height int) *Grid[T] {
+	return &Grid[T]{
+		Data:   make([]T, width*height),
+		Width:  width,
+		Height: height,
+		Stride: width,
+	}
+}
+
+// GridFromSlice creates a new grid by copying a [][]T tilemap
+func GridFromSlice[T Integer](tileMap [][]T) *Grid[T] {
+	if len(tileMap) == 0 {
+		return NewGrid[T](0, 0)
+	}
+	g := NewGrid[T](len(tileMap[0]), len(tileMap))
+	for y, row := range tileMap {
+		copy(g.Data[y*g.Stride:y*g.Stride+g.Width], row)
+	}
+	return g
+}
+
+// Slice returns a copy of the grid as a [][]T tilemap
+func (g *Grid[T]) Slice() [][]T {
+	tileMap := make([][]T, g.Height)
+	for y := range tileMap {
+		tileMap[y] = make([]T, g.Width)
+		copy(tileMap[y], g.Data[y*g.Stride:y*g.Stride+g.Width])
+	}
+	return tileMap
+}
+
+// Get returns the tile ID at the given tile coordinates
+func (g *Grid[T]) Get(x, y int) T {
+	return g.Data[y*g.Stride+x]
+}
+
+// Set sets the tile ID at the given tile coordinates
+func (g *Grid[T]) Set(x, y int, id T) {
+	g.Data[y*g.Stride+x] = id
+}
+
+// Bounds returns the tile coordinate bounds of the grid
+func (g *Grid[T]) Bounds() (minX, minY, maxX, maxY int) {
+	return 0, 0, g.Width, g.Height
+}
@@ -0,0 +1,50 @@
+package tilecollider
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestGridFromSlice(t *testing.T) {
+	m := [][]int{{1, 2, 3}, {4, 5, 6}}
+	g := GridFromSlice(m)
+	if g.Width != 3 || g.Height != 2 || g.Stride != 3 {
+		t.Fatalf("got size %dx%d stride %d", g.Width, g.Height, g.Stride)
+	}
+	if g.Get(2, 1) != 6 || g.Get(0, 1) != 4 {
+		t.Errorf("Get returned wrong tiles: %v", g.Data)
+	}
+	g.Set(1, 0, 9)
+	if s := g.Slice(); !slices.Equal(s[0], []int{1, 9, 3}) || !slices.Equal(s[1], []int{4, 5, 6}) {
+		t.Errorf("Slice got %v", s)
+	}
+	if m[0][1] != 2 {
+		t.Error("GridFromSlice does not copy the tilemap")
+	}
+}
+
+func TestGridMatchesReference(t *testing.T) {
+	checkEquivalent(t, 20000, func(m [][]uint8, tw, th int) *Collider[uint8] {
+		return NewColliderSource[uint8](GridFromSlice(m), tw, th)
+	})
+}
+
+// benchmarkSweep moves tiles down across 1024 rows of the 4096x4096 benchMap.
+// Every tile read is in a different row, so [][]T loads a row header before each tile while Grid does not.
+func benchmarkSweep(b *testing.B, c *Collider[uint8]) {
+	r := rand.New(rand.NewSource(3))
+	for b.Loop() {
+		c.CollideY(r.Float64()*4000*16, r.Float64()*3000*16, 16, 16, 1024*16)
+	}
+}
+
+// BenchmarkSweepSliceMap and BenchmarkSweepGrid compare [][]T and Grid storage on long vertical sweeps.
+// Random short moves (BenchmarkCollide) touch too few rows for the storage to matter.
+func BenchmarkSweepSliceMap(b *testing.B) {
+	benchmarkSweep(b, NewCollider(benchMap, 16, 16))
+}
+
+func BenchmarkSweepGrid(b *testing.B) {
+	benchmarkSweep(b, NewColliderSource[uint8](GridFromSlice(benchMap), 16, 16))
+}
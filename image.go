@@ -0,0 +1,46 @@
+package tilecollider
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+// ImageTileMap converts an image to a [y][x]T tilemap. Each pixel is a tile and its color is mapped to a tile ID with the palette.
+// It returns an error for colors that are not in the palette.
+func ImageTileMap[T Integer](img image.Image, palette map[color.NRGBA]T) ([][]T, error) {
+	b := img.Bounds()
+	tileMap := make([][]T, b.Dy())
+	for y := range tileMap {
+		tileMap[y] = make([]T, b.Dx())
+		for x := range tileMap[y] {
+			c := color.NRGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.NRGBA)
+			id, ok := palette[c]
+			if !ok {
+				return nil, fmt.Errorf("tilecollider: unmapped color %v at %d,%d", c, x, y)
+			}
+			tileMap[y][x] = id
+		}
+	}
+	return tileMap, nil
+}
+
+// DecodePNG decodes a PNG image and converts it to a [y][x]T tilemap. See ImageTileMap.
+func DecodePNG[T Integer](r io.Reader, palette map[color.NRGBA]T) ([][]T, error) {
+	img, err := png.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+	return ImageTileMap(img, palette)
+}
+
+// NewColliderImage creates a new tile collider from an image. See ImageTileMap.
+func NewColliderImage[T Integer](img image.Image, palette map[color.NRGBA]T, tileWidth, tileHeight int) (*Collider[T], error) {
+	tileMap, err := ImageTileMap(img, palette)
+	if err != nil {
+		return nil, err
+	}
+	return NewCollider(tileMap, tileWidth, tileHeight), nil
+}
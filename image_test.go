@@ -0,0 +1,62 @@
+package tilecollider
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var levelPalette = map[color.NRGBA]uint8{
+	{0, 0, 0, 255}:       0,
+	{255, 255, 255, 255}: 1,
+	{0, 0, 255, 255}:     2,
+}
+
+func TestDecodePNG(t *testing.T) {
+	f, err := os.Open("testdata/level.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got, err := DecodePNG(f, levelPalette)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]uint8{{0, 0, 0, 0}, {0, 0, 0, 1}, {1, 1, 2, 1}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestImageTileMapOffsetBounds(t *testing.T) {
+	// Paletted images with non-zero bounds are read from their minimum point
+	img := image.NewPaletted(image.Rect(2, 3, 5, 5), color.Palette{color.Black, color.White, color.RGBA{0, 0, 255, 255}})
+	img.SetColorIndex(3, 4, 1)
+	img.SetColorIndex(4, 3, 2)
+	c, err := NewColliderImage(img, levelPalette, 16, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]uint8{{0, 0, 2}, {0, 1, 0}}; !reflect.DeepEqual(c.TileMap, want) {
+		t.Errorf("got %v want %v", c.TileMap, want)
+	}
+	if c.TileSize != [2]int{16, 8} {
+		t.Errorf("got TileSize %v", c.TileSize)
+	}
+}
+
+func TestImageTileMapUnmappedColor(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+	img.SetNRGBA(0, 0, color.NRGBA{0, 0, 0, 255})
+	img.SetNRGBA(1, 0, color.NRGBA{255, 0, 0, 255})
+	_, err := ImageTileMap(img, levelPalette)
+	if err == nil || !strings.Contains(err.Error(), "at 1,0") {
+		t.Errorf("got error %v", err)
+	}
+	if _, err := DecodePNG(strings.NewReader("not a png"), levelPalette); err == nil {
+		t.Error("decoded an invalid PNG")
+	}
+}
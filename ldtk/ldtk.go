@@ -0,0 +1,305 @@
+// Package ldtk loads LDtk (https://ldtk.io) projects and builds tilecollider colliders from IntGrid layers.
+package ldtk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/setanarut/tilecollider"
+)
+
+var (
+	ErrLevelNotFound = errors.New("ldtk: level not found")
+	ErrLayerNotFound = errors.New("ldtk: layer not found")
+	ErrExternalLevel = errors.New("ldtk: external level is not loaded")
+)
+
+// Layer types
+const (
+	IntGrid   = "IntGrid"
+	Entities  = "Entities"
+	Tiles     = "Tiles"
+	AutoLayer = "AutoLayer"
+)
+
+// Project is a decoded LDtk project
+type Project struct {
+	Levels []*Level
+}
+
+// Level is a decoded LDtk level
+type Level struct {
+	Identifier      string
+	IID             string
+	World           [2]int   // X,Y position of the level in world pixels
+	Size            [2]int   // Width and height of the level in pixels
+	ExternalRelPath string   // Path of the external level file relative to the project, if any
+	Layers          []*Layer // Layer instances, nil if the level is external and not loaded
+}
+
+// Layer is a decoded LDtk layer instance
+type Layer struct {
+	Identifier string
+	Type       string   // IntGrid, Entities, Tiles or AutoLayer
+	Width      int      // Width of the layer in cells
+	Height     int      // Height of the layer in cells
+	GridSize   int      // Size of cells in pixels
+	Offset     [2]int   // Total X,Y pixel offset of the layer inside the level
+	IntGrid    []int    // IntGrid values in row-major order. 0 is an empty cell.
+	Entities   []Entity // Entity instances of an Entities layer
+}
+
+// Entity is a decoded LDtk entity instance
+type Entity struct {
+	Identifier string
+	IID        string
+	Grid       [2]int                     // X,Y cell coordinates in the layer
+	Px         [2]int                     // X,Y pixel position in the layer, including the pivot
+	World      [2]int                     // X,Y pixel position in the world
+	Pivot      [2]float64                 // Pivot of the entity (0-1)
+	Size       [2]int                     // Width and height in pixels
+	Fields     map[string]json.RawMessage // Raw field values keyed by field identifier
+}
+
+// Field decodes the value of the entity field into v
+func (e *Entity) Field(name string, v any) error {
+	raw, ok := e.Fields[name]
+	if !ok {
+		return fmt.Errorf("ldtk: entity %q has no field %q", e.Identifier, name)
+	}
+	return json.Unmarshal(raw, v)
+}
+
+type jsonProject struct {
+	Levels []jsonLevel `json:"levels"`
+}
+
+type jsonLevel struct {
+	Identifier      string      `json:"identifier"`
+	IID             string      `json:"iid"`
+	WorldX          int         `json:"worldX"`
+	WorldY          int         `json:"worldY"`
+	PxWid           int         `json:"pxWid"`
+	PxHei           int         `json:"pxHei"`
+	ExternalRelPath string      `json:"externalRelPath"`
+	LayerInstances  []jsonLayer `json:"layerInstances"`
+}
+
+type jsonLayer struct {
+	Identifier      string       `json:"__identifier"`
+	Type            string       `json:"__type"`
+	CWid            int          `json:"__cWid"`
+	CHei            int          `json:"__cHei"`
+	GridSize        int          `json:"__gridSize"`
+	PxTotalOffsetX  int          `json:"__pxTotalOffsetX"`
+	PxTotalOffsetY  int          `json:"__pxTotalOffsetY"`
+	IntGridCsv      []int        `json:"intGridCsv"`
+	EntityInstances []jsonEntity `json:"entityInstances"`
+}
+
+type jsonEntity struct {
+	Identifier     string      `json:"__identifier"`
+	IID            string      `json:"iid"`
+	Grid           [2]int      `json:"__grid"`
+	Pivot          [2]float64  `json:"__pivot"`
+	Px             [2]int      `json:"px"`
+	Width          int         `json:"width"`
+	Height         int         `json:"height"`
+	FieldInstances []jsonField `json:"fieldInstances"`
+}
+
+type jsonField struct {
+	Identifier string          `json:"__identifier"`
+	Value      json.RawMessage `json:"__value"`
+}
+
+// Load loads a .ldtk project file. External levels are loaded relative to the project file.
+func Load(path string) (*Project, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	p, err := Decode(f)
+	if err != nil {
+		return nil, err
+	}
+	for i, l := range p.Levels {
+		if l.Layers != nil || l.ExternalRelPath == "" {
+			continue
+		}
+		if p.Levels[i], err = loadLevel(filepath.Join(filepath.Dir(path), l.ExternalRelPath)); err != nil {
+			return nil, err
+		}
+		p.Levels[i].ExternalRelPath = l.ExternalRelPath
+	}
+	return p, nil
+}
+
+// Decode decodes an LDtk project. External levels are not loaded; their Layers are nil.
+func Decode(r io.Reader) (*Project, error) {
+	var jp jsonProject
+	if err := json.NewDecoder(r).Decode(&jp); err != nil {
+		return nil, fmt.Errorf("ldtk: %w", err)
+	}
+	p := &Project{}
+	for _, jl := range jp.Levels {
+		p.Levels = append(p.Levels, jl.level())
+	}
+	return p, nil
+}
+
+// loadLevel loads an external .ldtkl level file
+func loadLevel(path string) (*Level, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var jl jsonLevel
+	if err := json.NewDecoder(f).Decode(&jl); err != nil {
+		return nil, fmt.Errorf("ldtk: %s: %w", path, err)
+	}
+	return jl.level(), nil
+}
+
+func (jl jsonLevel) level() *Level {
+	l := &Level{
+		Identifier:      jl.Identifier,
+		IID:             jl.IID,
+		World:           [2]int{jl.WorldX, jl.WorldY},
+		Size:            [2]int{jl.PxWid, jl.PxHei},
+		ExternalRelPath: jl.ExternalRelPath,
+	}
+	if jl.LayerInstances == nil {
+		return l
+	}
+	l.Layers = make([]*Layer, 0, len(jl.LayerInstances))
+	for _, jy := range jl.LayerInstances {
+		layer := &Layer{
+			Identifier: jy.Identifier,
+			Type:       jy.Type,
+			Width:      jy.CWid,
+			Height:     jy.CHei,
+			GridSize:   jy.GridSize,
+			Offset:     [2]int{jy.PxTotalOffsetX, jy.PxTotalOffsetY},
+			IntGrid:    jy.IntGridCsv,
+		}
+		for _, je := range jy.EntityInstances {
+			e := Entity{
+				Identifier: je.Identifier,
+				IID:        je.IID,
+				Grid:       je.Grid,
+				Px:         je.Px,
+				World:      [2]int{l.World[0] + layer.Offset[0] + je.Px[0], l.World[1] + layer.Offset[1] + je.Px[1]},
+				Pivot:      je.Pivot,
+				Size:       [2]int{je.Width, je.Height},
+				Fields:     make(map[string]json.RawMessage, len(je.FieldInstances)),
+			}
+			for _, f := range je.FieldInstances {
+				e.Fields[f.Identifier] = f.Value
+			}
+			layer.Entities = append(layer.Entities, e)
+		}
+		l.Layers = append(l.Layers, layer)
+	}
+	return l
+}
+
+// Level returns the level with the given identifier
+func (p *Project) Level(identifier string) (*Level, error) {
+	for _, l := range p.Levels {
+		if l.Identifier == identifier {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrLevelNotFound, identifier)
+}
+
+// Layer returns the layer with the given identifier
+func (l *Level) Layer(identifier string) (*Layer, error) {
+	if l.Layers == nil && l.ExternalRelPath != "" {
+		return nil, fmt.Errorf("%w: %q", ErrExternalLevel, l.Identifier)
+	}
+	for _, layer := range l.Layers {
+		if layer.Identifier == identifier {
+			return layer, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q in level %q", ErrLayerNotFound, identifier, l.Identifier)
+}
+
+// Entities returns the entity instances of all Entities layers of the level
+func (l *Level) Entities() []Entity {
+	var entities []Entity
+	for _, layer := range l.Layers {
+		entities = append(entities, layer.Entities...)
+	}
+	return entities
+}
+
+// ToLocal converts world pixel coordinates to the collider coordinates of the layer
+func (l *Level) ToLocal(layer *Layer, worldX, worldY float64) (float64, float64) {
+	return worldX - float64(l.World[0]+layer.Offset[0]), worldY - float64(l.World[1]+layer.Offset[1])
+}
+
+// ToWorld converts collider coordinates of the layer to world pixel coordinates
+func (l *Level) ToWorld(layer *Layer, x, y float64) (float64, float64) {
+	return x + float64(l.World[0]+layer.Offset[0]), y + float64(l.World[1]+layer.Offset[1])
+}
+
+// TileMap converts the IntGrid values of the layer to a [y][x]T tilemap.
+// It returns an error if a value does not fit in T.
+func TileMap[T tilecollider.Integer](layer *Layer) ([][]T, error) {
+	if layer.Type != IntGrid {
+		return nil, fmt.Errorf("ldtk: layer %q is not an IntGrid layer", layer.Identifier)
+	}
+	if len(layer.IntGrid) != layer.Width*layer.Height {
+		return nil, fmt.Errorf("ldtk: layer %q has %d cells, want %d", layer.Identifier, len(layer.IntGrid), layer.Width*layer.Height)
+	}
+	tileMap := make([][]T, layer.Height)
+	for y := range tileMap {
+		tileMap[y] = make([]T, layer.Width)
+		for x := range tileMap[y] {
+			v := layer.IntGrid[y*layer.Width+x]
+			id := T(v)
+			if int64(id) != int64(v) || (id < 0) != (v < 0) {
+				return nil, fmt.Errorf("ldtk: value %d at %d,%d of layer %q overflows tile type", v, x, y, layer.Identifier)
+			}
+			tileMap[y][x] = id
+		}
+	}
+	return tileMap, nil
+}
+
+// NewCollider creates a collider from the IntGrid layer of the level with TileSize set to the layer grid size.
+// Collider coordinates are relative to the layer origin; use Level.ToLocal and Level.ToWorld to convert.
+func NewCollider[T tilecollider.Integer](l *Level, layerIdentifier string) (*tilecollider.Collider[T], error) {
+	layer, err := l.Layer(layerIdentifier)
+	if err != nil {
+		return nil, err
+	}
+	tileMap, err := TileMap[T](layer)
+	if err != nil {
+		return nil, err
+	}
+	return tilecollider.NewCollider(tileMap, layer.GridSize, layer.GridSize), nil
+}
+
+// Colliders creates one collider per level from the IntGrid layer with the given identifier.
+// The colliders are in the same order as Project.Levels.
+func Colliders[T tilecollider.Integer](p *Project, layerIdentifier string) ([]*tilecollider.Collider[T], error) {
+	colliders := make([]*tilecollider.Collider[T], len(p.Levels))
+	for i, l := range p.Levels {
+		c, err := NewCollider[T](l, layerIdentifier)
+		if err != nil {
+			return nil, err
+		}
+		colliders[i] = c
+	}
+	return colliders, nil
+}
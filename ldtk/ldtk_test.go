@@ -0,0 +1,118 @@
+package ldtk
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	p, err := Load("testdata/world.ldtk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	colliders, err := Colliders[uint8](p, "Collisions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(colliders) != 2 {
+		t.Fatalf("got %d colliders, want 2", len(colliders))
+	}
+
+	start := colliders[0]
+	if start.TileSize != [2]int{16, 16} {
+		t.Errorf("got TileSize %v", start.TileSize)
+	}
+	if want := [][]uint8{{0, 0, 0, 0}, {0, 0, 0, 1}, {1, 1, 2, 1}}; !reflect.DeepEqual(start.TileMap, want) {
+		t.Errorf("got tilemap %v want %v", start.TileMap, want)
+	}
+
+	// The external level is loaded relative to the project file
+	cave, err := p.Level("Cave")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cave.ExternalRelPath != "world/Cave.ldtkl" || cave.World != [2]int{320, -64} {
+		t.Errorf("got external level %+v", cave)
+	}
+	if colliders[1].TileSize != [2]int{8, 8} || !reflect.DeepEqual(colliders[1].TileMap, [][]uint8{{1, 0}, {0, 1}}) {
+		t.Errorf("got cave collider %v %v", colliders[1].TileSize, colliders[1].TileMap)
+	}
+}
+
+func TestEntitiesAndWorldOffsets(t *testing.T) {
+	p, err := Load("testdata/world.ldtk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := p.Level("Start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entities := l.Entities()
+	if len(entities) != 1 {
+		t.Fatalf("got %d entities", len(entities))
+	}
+	player := entities[0]
+	if player.Identifier != "Player" || player.Grid != [2]int{1, 1} || player.Size != [2]int{16, 16} {
+		t.Errorf("got entity %+v", player)
+	}
+	// Level at 256,-64 plus the entity position 24,32
+	if player.World != [2]int{280, -32} {
+		t.Errorf("got entity world position %v", player.World)
+	}
+	var hp int
+	var name string
+	if err := player.Field("hp", &hp); err != nil || hp != 3 {
+		t.Errorf("hp field got %v, %v", hp, err)
+	}
+	if err := player.Field("name", &name); err != nil || name != "hero" {
+		t.Errorf("name field got %v, %v", name, err)
+	}
+	if err := player.Field("missing", &hp); err == nil {
+		t.Error("missing field decoded")
+	}
+
+	// The collision layer is offset by 8,4 inside the level
+	layer, err := l.Layer("Collisions")
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := l.ToLocal(layer, 280, -32)
+	if x != 16 || y != 28 {
+		t.Errorf("ToLocal got %v,%v", x, y)
+	}
+	if wx, wy := l.ToWorld(layer, x, y); wx != 280 || wy != -32 {
+		t.Errorf("ToWorld got %v,%v", wx, wy)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	f := strings.NewReader(`{"levels":[{"identifier":"Ext","externalRelPath":"Ext.ldtkl","layerInstances":null}]}`)
+	p, err := Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Colliders[uint8](p, "Collisions"); !errors.Is(err, ErrExternalLevel) {
+		t.Errorf("external level got %v", err)
+	}
+	if _, err := p.Level("Missing"); !errors.Is(err, ErrLevelNotFound) {
+		t.Errorf("missing level got %v", err)
+	}
+
+	p, err = Load("testdata/world.ldtk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, _ := p.Level("Start")
+	if _, err := NewCollider[uint8](l, "Missing"); !errors.Is(err, ErrLayerNotFound) {
+		t.Errorf("missing layer got %v", err)
+	}
+	if _, err := NewCollider[uint8](l, "Entities"); err == nil {
+		t.Error("Entities layer converted to a collider")
+	}
+	if _, err := TileMap[int8](&Layer{Type: IntGrid, Width: 1, Height: 1, IntGrid: []int{200}}); err == nil {
+		t.Error("value 200 fits in int8")
+	}
+}
@@ -0,0 +1,123 @@
+package tilecollider
+
+// TileChange stores information about a changed tile
+type TileChange[T Integer] struct {
+	TileCoords [2]int // X,Y coordinates of the tile in the tilemap
+	OldID      T      // ID of the tile before the change
+	NewID      T      // ID of the tile after the change
+}
+
+// TileWatcher receives tile changes made with SetTile and FillRect, and accumulates dirty regions
+type TileWatcher[T Integer] struct {
+	OnChange func(TileChange[T]) // Optional. Called for every changed tile.
+	dirty    [][4]int
+}
+
+// Dirty returns the accumulated dirty regions as X,Y,W,H rectangles in tile coordinates
+func (w *TileWatcher[T]) Dirty() [][4]int {
+	return w.dirty
+}
+
+// Drain returns the accumulated dirty regions and clears them
+func (w *TileWatcher[T]) Drain() [][4]int {
+	dirty := w.dirty
+	w.dirty = nil
+	return dirty
+}
+
+// addDirty adds a region unless an existing region already contains it
+func (w *TileWatcher[T]) addDirty(r [4]int) {
+	for _, d := range w.dirty {
+		if r[0] >= d[0] && r[1] >= d[1] && r[0]+r[2] <= d[0]+d[2] && r[1]+r[3] <= d[1]+d[3] {
+			return
+		}
+	}
+	w.dirty = append(w.dirty, r)
+}
+
+// Watch registers a new watcher that is notified about tile changes. onChange may be nil.
+func (c *ColliderOf[T, F]) Watch(onChange func(TileChange[T])) *TileWatcher[T] {
+	w := &TileWatcher[T]{OnChange: onChange}
+	c.watchers = append(c.watchers, w)
+	return w
+}
+
+// Unwatch removes the watcher
+func (c *ColliderOf[T, F]) Unwatch(w *TileWatcher[T]) {
+	for i, v := range c.watchers {
+		if v == w {
+			c.watchers = append(c.watchers[:i], c.watchers[i+1:]...)
+			return
+		}
+	}
+}
+
+// SetTile sets the tile ID at the given tile coordinates and notifies watchers.
+// It returns false if the coordinates are outside the tilemap bounds or the Source is read-only.
+func (c *ColliderOf[T, F]) SetTile(x, y int, id T) bool {
+	changed, ok := c.setTile(x, y, id)
+	if changed {
+		for _, w := range c.watchers {
+			w.addDirty([4]int{x, y, 1, 1})
+		}
+	}
+	return ok
+}
+
+// FillRect sets all tiles in the X,Y,W,H rectangle (in tile coordinates) to the tile ID and notifies watchers.
+// The rectangle is clipped to the tilemap bounds. It returns the number of changed tiles.
+func (c *ColliderOf[T, F]) FillRect(x, y, w, h int, id T) int {
+	minX, minY, maxX, maxY := c.bounds()
+	left, top := max(x, minX), max(y, minY)
+	right, bottom := min(x+w, maxX), min(y+h, maxY)
+
+	count := 0
+	dirtyLeft, dirtyTop, dirtyRight, dirtyBottom := right, bottom, left, top
+	for ty := top; ty < bottom; ty++ {
+		for tx := left; tx < right; tx++ {
+			changed, ok := c.setTile(tx, ty, id)
+			if !ok {
+				return count
+			}
+			if changed {
+				count++
+				dirtyLeft, dirtyTop = min(dirtyLeft, tx), min(dirtyTop, ty)
+				dirtyRight, dirtyBottom = max(dirtyRight, tx+1), max(dirtyBottom, ty+1)
+			}
+		}
+	}
+
+	if count > 0 {
+		for _, wt := range c.watchers {
+			wt.addDirty([4]int{dirtyLeft, dirtyTop, dirtyRight - dirtyLeft, dirtyBottom - dirtyTop})
+		}
+	}
+	return count
+}
+
+// setTile writes the tile and calls OnChange of the watchers if the tile ID changed
+func (c *ColliderOf[T, F]) setTile(x, y int, id T) (changed, ok bool) {
+	old, ok := c.Tile(x, y)
+	if !ok {
+		return false, false
+	}
+	if old == id {
+		return false, true
+	}
+
+	switch s := c.Source.(type) {
+	case nil:
+		c.TileMap[y][x] = id
+	case TileSetter[T]:
+		s.Set(x, y, id)
+	default:
+		return false, false
+	}
+
+	for _, w := range c.watchers {
+		if w.OnChange != nil {
+			w.OnChange(TileChange[T]{TileCoords: [2]int{x, y}, OldID: old, NewID: id})
+		}
+	}
+	return true, true
+}
@@ -0,0 +1,59 @@
+package tilecollider
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFillRectAndWatch(t *testing.T) {
+	colliders := map[string]*Collider[uint8]{
+		"slice":  NewCollider(NewGrid[uint8](5, 5).Slice(), 8, 8),
+		"grid":   NewColliderSource[uint8](NewGrid[uint8](5, 5), 8, 8),
+		"sparse": NewColliderSparse(NewSparseMap[uint8](5, 5, 0), 8, 8),
+	}
+	for name, c := range colliders {
+		t.Run(name, func(t *testing.T) {
+			var changes []TileChange[uint8]
+			w := c.Watch(func(tc TileChange[uint8]) { changes = append(changes, tc) })
+
+			// Clipped to x 0..1 and y 1..4
+			if n := c.FillRect(-1, 1, 3, 10, 2); n != 8 || len(changes) != 8 {
+				t.Fatalf("FillRect changed %d tiles with %d events, want 8", n, len(changes))
+			}
+			if changes[0] != (TileChange[uint8]{TileCoords: [2]int{0, 1}, OldID: 0, NewID: 2}) {
+				t.Errorf("got first change %+v", changes[0])
+			}
+			if n := c.FillRect(0, 1, 2, 2, 2); n != 0 {
+				t.Errorf("refilling changed %d tiles", n)
+			}
+			if !c.SetTile(1, 1, 2) || c.SetTile(9, 9, 1) {
+				t.Error("SetTile reports wrong bounds")
+			}
+			// Writing the current ID outside every region changes nothing and adds no region
+			if !c.SetTile(3, 4, 0) {
+				t.Error("SetTile with the current ID failed")
+			}
+			c.SetTile(4, 0, 1)
+			if len(changes) != 9 {
+				t.Errorf("got %d change events, want 9", len(changes))
+			}
+
+			want := [][4]int{{0, 1, 2, 4}, {4, 0, 1, 1}}
+			if d := w.Drain(); !slices.Equal(d, want) {
+				t.Errorf("got dirty %v, want %v", d, want)
+			}
+			if len(w.Dirty()) != 0 {
+				t.Error("Drain does not clear the dirty regions")
+			}
+			if id, _ := c.Tile(1, 3); id != 2 {
+				t.Errorf("got tile %d, want 2", id)
+			}
+
+			c.Unwatch(w)
+			c.SetTile(3, 3, 1)
+			if len(changes) != 9 || len(w.Dirty()) != 0 {
+				t.Error("unwatched watcher is still notified")
+			}
+		})
+	}
+}
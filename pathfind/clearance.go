@@ -0,0 +1,109 @@
+package pathfind
+
+import (
+	"math"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// ClearanceMap stores the true clearance of every tile of a bounded tilemap.
+//
+// The clearance of a tile is the size of the largest square of non-solid tiles whose top-left corner is that tile,
+// capped at Max. Tiles outside the map count as blocked.
+type ClearanceMap[T tilecollider.Integer] struct {
+	Collider  *tilecollider.Collider[T]
+	Max       int   // Largest clearance stored. Agents larger than Max fall back to scanning tiles.
+	Clearance []int // Clearance of each tile in row-major order
+
+	bounds [4]int // minX, minY, width, height
+}
+
+// NewClearanceMap creates and computes a clearance map capped at max.
+// It returns ErrUnbounded if the collider's tile source is unbounded.
+func NewClearanceMap[T tilecollider.Integer](c *tilecollider.Collider[T], max int) (*ClearanceMap[T], error) {
+	m := &ClearanceMap[T]{Collider: c, Max: max}
+	if err := m.Compute(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Compute recomputes the whole clearance map
+func (m *ClearanceMap[T]) Compute() error {
+	minX, minY, maxX, maxY, err := boundedBounds(m.Collider, "ClearanceMap")
+	if err != nil {
+		return err
+	}
+	m.bounds = [4]int{minX, minY, maxX - minX, maxY - minY}
+	m.Clearance = make([]int, m.bounds[2]*m.bounds[3])
+	m.computeRect(minX, minY, maxX, maxY)
+	return nil
+}
+
+// Update recomputes the clearance after the tiles in the dirty regions changed.
+// Regions are X,Y,W,H rectangles in tile coordinates, e.g. from tilecollider.TileWatcher.Drain.
+// If the map bounds changed, the whole map is recomputed (see Compute).
+func (m *ClearanceMap[T]) Update(dirty [][4]int) error {
+	minX, minY, maxX, maxY := m.Collider.Bounds()
+	if [4]int{minX, minY, maxX - minX, maxY - minY} != m.bounds {
+		return m.Compute()
+	}
+	for _, r := range dirty {
+		// A tile only affects the clearance of tiles up to Max-1 tiles above and to the left of it
+		m.computeRect(
+			max(r[0]-m.Max+1, minX),
+			max(r[1]-m.Max+1, minY),
+			min(r[0]+r[2], maxX),
+			min(r[1]+r[3], maxY),
+		)
+	}
+	return nil
+}
+
+// At returns the clearance of the tile. It is 0 outside the map and for solid tiles.
+func (m *ClearanceMap[T]) At(x, y int) int {
+	x, y = x-m.bounds[0], y-m.bounds[1]
+	if x < 0 || y < 0 || x >= m.bounds[2] || y >= m.bounds[3] {
+		return 0
+	}
+	return m.Clearance[y*m.bounds[2]+x]
+}
+
+// computeRect recomputes the clearance of the tiles in [x0, x1) x [y0, y1) from the bottom-right corner
+func (m *ClearanceMap[T]) computeRect(x0, y0, x1, y1 int) {
+	for y := y1 - 1; y >= y0; y-- {
+		for x := x1 - 1; x >= x0; x-- {
+			clearance := 0
+			if id, _ := m.Collider.Tile(x, y); !m.Collider.IsSolid(id) {
+				clearance = min(m.Max, 1+min(m.At(x+1, y), m.At(x, y+1), m.At(x+1, y+1)))
+			}
+			m.Clearance[(y-m.bounds[1])*m.bounds[2]+x-m.bounds[0]] = clearance
+		}
+	}
+}
+
+// AgentTiles returns the agent size in tiles for a rectangle of w by h pixels.
+// A rectangle aligned to the top-left corner of a tile overlaps exactly that many tiles on its longer axis,
+// matching the tiles tested by Collider.Collide.
+func AgentTiles[T tilecollider.Integer](c *tilecollider.Collider[T], w, h float64) int {
+	return max(1,
+		int(math.Ceil(w/float64(c.TileSize[0]))),
+		int(math.Ceil(h/float64(c.TileSize[1]))),
+	)
+}
+
+// fits reports whether an agent of opts.AgentSize tiles with its top-left corner at the tile overlaps no solid tile
+func fits[T tilecollider.Integer](c *tilecollider.Collider[T], opts *Options[T], x, y int) bool {
+	if opts.Clearance != nil && opts.AgentSize <= opts.Clearance.Max {
+		return opts.Clearance.At(x, y) >= opts.AgentSize
+	}
+	for dy := range opts.AgentSize {
+		for dx := range opts.AgentSize {
+			id, ok := c.Tile(x+dx, y+dy)
+			if !ok || c.IsSolid(id) {
+				return false
+			}
+		}
+	}
+	return true
+}
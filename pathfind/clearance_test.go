@@ -0,0 +1,183 @@
+package pathfind
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+func TestClearanceMap(t *testing.T) {
+	c, err := tilecollider.NewColliderASCII[uint8](`
+		....
+		..#.
+		....`, nil, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := must(NewClearanceMap(c, 3))
+	want := []int{
+		2, 1, 1, 1,
+		2, 1, 0, 1,
+		1, 1, 1, 1,
+	}
+	for i, v := range want {
+		if got := m.At(i%4, i/4); got != v {
+			t.Errorf("At(%d, %d) = %d, want %d", i%4, i/4, got, v)
+		}
+	}
+	if got := m.At(-1, 0); got != 0 {
+		t.Errorf("At outside = %d, want 0", got)
+	}
+}
+
+// TestClearanceUpdate checks incremental updates of clearance maps and flow fields for large agents
+// against full recomputation, and that paths of large agents never collide
+func TestClearanceUpdate(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := range 300 {
+		w, h := 5+r.Intn(25), 5+r.Intn(25)
+		m := make([][]int, h)
+		for y := range m {
+			m[y] = make([]int, w)
+			for x := range m[y] {
+				if r.Float64() < 0.12 {
+					m[y][x] = 1
+				}
+			}
+		}
+		c := tilecollider.NewCollider(m, 8, 6)
+		size := 1 + r.Intn(3)
+		clearance := must(NewClearanceMap(c, 3))
+		scan := &Options[int]{Diagonal: r.Intn(2) == 0, AgentSize: size}
+		fast := &Options[int]{Diagonal: scan.Diagonal, AgentSize: size, Clearance: clearance}
+		goals := [][2]int{{r.Intn(w), r.Intn(h)}}
+		f := must(NewFlowField(c, goals, fast))
+		watcher := c.Watch(nil)
+		for step := range 6 {
+			for range 1 + r.Intn(3) {
+				c.FillRect(r.Intn(w), r.Intn(h), 1+r.Intn(3), 1+r.Intn(3), r.Intn(2))
+			}
+			dirty := watcher.Drain()
+			if err := clearance.Update(dirty); err != nil {
+				t.Fatal(err)
+			}
+			if err := f.Update(dirty); err != nil {
+				t.Fatal(err)
+			}
+
+			full := must(NewClearanceMap(c, 3))
+			for j := range full.Clearance {
+				if clearance.Clearance[j] != full.Clearance[j] {
+					t.Fatalf("case %d step %d: tile %d: updated clearance %d, recomputed %d", i, step, j, clearance.Clearance[j], full.Clearance[j])
+				}
+			}
+			want := must(NewFlowField(c, goals, scan))
+			for j := range want.Integration {
+				if a, b := f.Integration[j], want.Integration[j]; a != b && math.Abs(a-b) > 1e-9 {
+					t.Fatalf("case %d step %d: tile %d: updated cost %v, recomputed %v", i, step, j, a, b)
+				}
+			}
+
+			start, goal := [2]int{r.Intn(w), r.Intn(h)}, [2]int{r.Intn(w), r.Intn(h)}
+			p1, ok1 := FindPath(c, start, goal, scan)
+			p2, ok2 := FindPath(c, start, goal, fast)
+			if ok1 != ok2 || math.Abs(p1.Cost-p2.Cost) > 1e-9 {
+				t.Fatalf("case %d step %d: scanned path %v %v, clearance path %v %v", i, step, ok1, p1.Cost, ok2, p2.Cost)
+			}
+			rw, rh := float64(size*8), float64(size*6)
+			if got := AgentTiles(c, rw, rh); got != size {
+				t.Fatalf("AgentTiles(%v, %v) = %d, want %d", rw, rh, got, size)
+			}
+			for k, tile := range p1.Tiles {
+				dx, dy := c.Collide(float64(tile[0]*8), float64(tile[1]*6), rw, rh, 0, 0, nil)
+				if dx != 0 || dy != 0 || len(c.Collisions) != 0 {
+					t.Fatalf("case %d step %d: agent collides at %v", i, step, tile)
+				}
+				// World points are the centers of the agent
+				if want := [2]float64{float64(tile[0]*8) + rw/2, float64(tile[1]*6) + rh/2}; p1.World[k] != want {
+					t.Fatalf("World[%d] = %v, want %v", k, p1.World[k], want)
+				}
+			}
+		}
+	}
+}
+
+func TestAgentToWorld(t *testing.T) {
+	c := tilecollider.NewCollider[uint8](nil, 16, 8)
+	for _, size := range []int{0, 1, 2, 3, 4} {
+		for _, tile := range [][2]int{{0, 0}, {3, -2}, {-5, 7}} {
+			p := AgentToWorld(c, tile, size)
+			n := float64(max(size, 1))
+			if want := [2]float64{(float64(tile[0]) + n/2) * 16, (float64(tile[1]) + n/2) * 8}; p != want {
+				t.Errorf("AgentToWorld(%v, %d) = %v, want %v", tile, size, p, want)
+			}
+			if got := WorldToAgent(c, p[0], p[1], size); got != tile {
+				t.Errorf("WorldToAgent(AgentToWorld(%v, %d)) = %v", tile, size, got)
+			}
+			// Any position inside the center tile (or tile pair) maps to the same agent tile
+			if got := WorldToAgent(c, p[0]+7.9, p[1]-3.9, size); size%2 == 1 && got != tile {
+				t.Errorf("WorldToAgent near the center of %v, %d = %v", tile, size, got)
+			}
+		}
+	}
+}
+
+func TestFlowFieldDirectionLargeAgent(t *testing.T) {
+	c := tilecollider.NewCollider(make([][]uint8, 6), 16, 16)
+	for y := range c.TileMap {
+		c.TileMap[y] = make([]uint8, 6)
+	}
+	f := must(NewFlowField(c, [][2]int{{4, 0}}, &Options[uint8]{AgentSize: 2}))
+	// An agent at tile 0,0 is centered at 16,16 and must move right
+	if dx, dy, ok := f.Direction(16, 16); !ok || dx != 1 || dy != 0 {
+		t.Errorf("Direction = %v, %v, %v, want 1, 0, true", dx, dy, ok)
+	}
+	// The agent at the goal
+	p := AgentToWorld(c, [2]int{4, 0}, 2)
+	if dx, dy, ok := f.Direction(p[0], p[1]); !ok || dx != 0 || dy != 0 {
+		t.Errorf("Direction at goal = %v, %v, %v", dx, dy, ok)
+	}
+	// Agent tile 5,0 does not fit in the map
+	if _, _, ok := f.Direction(96, 16); ok {
+		t.Error("Direction ok for an agent that does not fit")
+	}
+}
+
+func TestConstructorErrors(t *testing.T) {
+	c := tilecollider.NewColliderSource[uint8](tilecollider.NewChunkMap[uint8](8, 8, 0, nil), 16, 16)
+	bounded := tilecollider.NewCollider([][]uint8{{0, 0}, {0, 0}}, 16, 16)
+	tests := []struct {
+		name string
+		fn   func() error
+		want error
+	}{
+		{"ClearanceMap", func() error { _, err := NewClearanceMap(c, 3); return err }, ErrUnbounded},
+		{"FlowField", func() error { _, err := NewFlowField(c, [][2]int{{0, 0}}, nil); return err }, ErrUnbounded},
+		{"Hierarchy", func() error { _, err := NewHierarchy(c, 8, nil); return err }, ErrUnbounded},
+		{"BuildNavGraph", func() error { _, err := BuildNavGraph(c, navConfig); return err }, ErrUnbounded},
+		{"zero cluster size", func() error { _, err := NewHierarchy(bounded, 0, nil); return err }, ErrClusterSize},
+		{"negative cluster size", func() error { _, err := NewHierarchy(bounded, -4, nil); return err }, ErrClusterSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fn(); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// Switching to an unbounded source makes updates fail instead of panicking
+	f := must(NewFlowField(bounded, [][2]int{{0, 0}}, nil))
+	bounded.Source = c.Source
+	if err := f.Update([][4]int{{0, 0, 1, 1}}); !errors.Is(err, ErrUnbounded) {
+		t.Errorf("Update err = %v, want %v", err, ErrUnbounded)
+	}
+
+	// Plain A* works with a node limit
+	if _, ok := FindPath(c, [2]int{0, 0}, [2]int{5, 5}, &Options[uint8]{MaxNodes: 100}); !ok {
+		t.Error("FindPath on an unbounded source failed")
+	}
+}
@@ -0,0 +1,229 @@
+package pathfind
+
+import (
+	"container/heap"
+	"math"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// FlowField stores the cost to the nearest goal and the direction to follow for every tile of a bounded tilemap.
+// Many agents heading to the same goals can share one flow field.
+type FlowField[T tilecollider.Integer] struct {
+	Collider    *tilecollider.Collider[T]
+	Options     Options[T] // MaxNodes is ignored
+	Goals       [][2]int   // X,Y tile coordinates of the goals
+	Integration []float64  // Cost to the nearest goal of each tile in row-major order. +Inf if unreachable.
+	Next        []int      // Index of the next tile toward the goal in Integration, or -1 for goals and unreachable tiles
+
+	bounds [4]int // minX, minY, width, height
+}
+
+// NewFlowField creates and computes a flow field for the goals. opts may be nil.
+// It returns ErrUnbounded if the collider's tile source is unbounded.
+func NewFlowField[T tilecollider.Integer](c *tilecollider.Collider[T], goals [][2]int, opts *Options[T]) (*FlowField[T], error) {
+	f := &FlowField[T]{Collider: c, Goals: goals}
+	if opts != nil {
+		f.Options = *opts
+	}
+	if err := f.Compute(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Compute recomputes the whole flow field
+func (f *FlowField[T]) Compute() error {
+	minX, minY, maxX, maxY, err := boundedBounds(f.Collider, "FlowField")
+	if err != nil {
+		return err
+	}
+	f.bounds = [4]int{minX, minY, maxX - minX, maxY - minY}
+	n := f.bounds[2] * f.bounds[3]
+	f.Integration = make([]float64, n)
+	f.Next = make([]int, n)
+	for i := range f.Integration {
+		f.Integration[i] = math.Inf(1)
+		f.Next[i] = -1
+	}
+	queue := &flowQueue{}
+	f.seedGoals(queue, nil)
+	f.propagate(queue)
+	return nil
+}
+
+// Update recomputes the flow field after the tiles in the dirty regions changed.
+// Regions are X,Y,W,H rectangles in tile coordinates, e.g. from tilecollider.TileWatcher.Drain.
+// Only tiles whose path to the goal passes through a dirty region, and tiles that can be improved by it, are recomputed.
+// If the map bounds changed, the whole field is recomputed (see Compute).
+func (f *FlowField[T]) Update(dirty [][4]int) error {
+	if len(dirty) == 0 {
+		return nil
+	}
+	minX, minY, maxX, maxY := f.Collider.Bounds()
+	if [4]int{minX, minY, maxX - minX, maxY - minY} != f.bounds {
+		return f.Compute()
+	}
+
+	// Invalidate dirty tiles and every tile whose flow passes through them.
+	// Regions are grown by one tile since a changed tile can also block diagonal moves around its corners,
+	// and by the agent size above and to the left since the agent covers the tiles to its bottom-right.
+	var stack []int
+	invalid := make(map[int]bool)
+	grow := max(f.Options.AgentSize, 1)
+	for _, r := range dirty {
+		for y := max(r[1]-grow, minY); y < min(r[1]+r[3]+1, maxY); y++ {
+			for x := max(r[0]-grow, minX); x < min(r[0]+r[2]+1, maxX); x++ {
+				i := f.index(x, y)
+				if !invalid[i] {
+					invalid[i] = true
+					stack = append(stack, i)
+				}
+			}
+		}
+	}
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		f.Integration[i] = math.Inf(1)
+		f.Next[i] = -1
+		for _, d := range allDirections {
+			x, y := f.tile(i)
+			x, y = x+d[0], y+d[1]
+			if x < minX || x >= maxX || y < minY || y >= maxY {
+				continue
+			}
+			if j := f.index(x, y); f.Next[j] == i && !invalid[j] {
+				invalid[j] = true
+				stack = append(stack, j)
+			}
+		}
+	}
+
+	// Propagate from the valid tiles around the invalidated area and from the goals
+	queue := &flowQueue{}
+	f.seedGoals(queue, invalid)
+	for i := range invalid {
+		x, y := f.tile(i)
+		for _, d := range allDirections {
+			nx, ny := x+d[0], y+d[1]
+			if nx < minX || nx >= maxX || ny < minY || ny >= maxY {
+				continue
+			}
+			if j := f.index(nx, ny); !invalid[j] && !math.IsInf(f.Integration[j], 1) {
+				heap.Push(queue, flowItem{j, f.Integration[j]})
+			}
+		}
+	}
+	f.propagate(queue)
+	return nil
+}
+
+// Direction returns the normalized direction to steer an agent centered at the world position toward the goal.
+// Larger agents are located by their top-left tile (see WorldToAgent).
+// ok is false if the position is outside the map or no goal is reachable from it. At a goal the direction is zero.
+func (f *FlowField[T]) Direction(x, y float64) (dx, dy float64, ok bool) {
+	tile := WorldToAgent(f.Collider, x, y, f.Options.AgentSize)
+	i, inside := f.lookup(tile)
+	if !inside || math.IsInf(f.Integration[i], 1) {
+		return 0, 0, false
+	}
+	if f.Next[i] < 0 {
+		return 0, 0, true
+	}
+	nx, ny := f.tile(f.Next[i])
+	dx, dy = float64(nx-tile[0]), float64(ny-tile[1])
+	length := math.Hypot(dx, dy)
+	return dx / length, dy / length, true
+}
+
+// Cost returns the cost from the tile to the nearest goal. It is +Inf if no goal is reachable.
+func (f *FlowField[T]) Cost(tile [2]int) float64 {
+	i, ok := f.lookup(tile)
+	if !ok {
+		return math.Inf(1)
+	}
+	return f.Integration[i]
+}
+
+// seedGoals pushes the walkable goals. If only is not nil, only goals in it are reset.
+func (f *FlowField[T]) seedGoals(queue *flowQueue, only map[int]bool) {
+	for _, g := range f.Goals {
+		i, ok := f.lookup(g)
+		if !ok || (only != nil && !only[i]) {
+			continue
+		}
+		if _, walkable := tileCost(f.Collider, &f.Options, g[0], g[1]); !walkable {
+			continue
+		}
+		f.Integration[i] = 0
+		f.Next[i] = -1
+		heap.Push(queue, flowItem{i, 0})
+	}
+}
+
+// propagate runs Dijkstra from the queued tiles toward their neighbors
+func (f *FlowField[T]) propagate(queue *flowQueue) {
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(flowItem)
+		if item.cost > f.Integration[item.index] {
+			continue
+		}
+		x, y := f.tile(item.index)
+		enter, ok := tileCost(f.Collider, &f.Options, x, y)
+		if !ok {
+			continue
+		}
+		// Agents move from the neighbor into this tile, so the step cost is the cost of entering this tile
+		forEachNeighbor(f.Collider, &f.Options, [2]int{x, y}, func(next [2]int, _ float64) {
+			step := enter
+			if next[0] != x && next[1] != y {
+				step *= math.Sqrt2
+			}
+			j := f.index(next[0], next[1])
+			if cost := item.cost + step; cost < f.Integration[j] {
+				f.Integration[j] = cost
+				f.Next[j] = item.index
+				heap.Push(queue, flowItem{j, cost})
+			}
+		})
+	}
+}
+
+func (f *FlowField[T]) index(x, y int) int {
+	return (y-f.bounds[1])*f.bounds[2] + x - f.bounds[0]
+}
+
+func (f *FlowField[T]) tile(i int) (x, y int) {
+	return i%f.bounds[2] + f.bounds[0], i/f.bounds[2] + f.bounds[1]
+}
+
+// lookup returns the index of the tile and whether it is inside the field
+func (f *FlowField[T]) lookup(tile [2]int) (int, bool) {
+	x, y := tile[0]-f.bounds[0], tile[1]-f.bounds[1]
+	if x < 0 || y < 0 || x >= f.bounds[2] || y >= f.bounds[3] {
+		return 0, false
+	}
+	return f.index(tile[0], tile[1]), true
+}
+
+var allDirections = [8][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}, {1, 1}, {-1, 1}, {-1, -1}, {1, -1}}
+
+type flowItem struct {
+	index int
+	cost  float64
+}
+
+// flowQueue is a min-heap of tiles ordered by cost
+type flowQueue []flowItem
+
+func (q flowQueue) Len() int           { return len(q) }
+func (q flowQueue) Less(i, j int) bool { return q[i].cost < q[j].cost }
+func (q flowQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *flowQueue) Push(x any)        { *q = append(*q, x.(flowItem)) }
+func (q *flowQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,140 @@
+package pathfind
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// randCostCollider returns a w by h collider with solid tiles (1) and expensive tiles (2)
+func randCostCollider(r *rand.Rand, w, h int) (*tilecollider.Collider[int], *Options[int]) {
+	m := make([][]int, h)
+	for y := range m {
+		m[y] = make([]int, w)
+		for x := range m[y] {
+			if r.Float64() < 0.25 {
+				m[y][x] = 1
+			} else if r.Float64() < 0.2 {
+				m[y][x] = 2
+			}
+		}
+	}
+	c := tilecollider.NewCollider(m, 8, 8)
+	c.SolidFunc = func(id int) bool { return id == 1 }
+	opts := &Options[int]{Diagonal: r.Intn(2) == 0, Cost: func(id, x, y int) float64 { return float64(1 + 3*(id/2)) }}
+	return c, opts
+}
+
+// checkFlowField fails if a tile's flow does not lead to a neighbor with a consistent cost,
+// or if its cost differs from the cheapest FindPath to a goal
+func checkFlowField[T tilecollider.Integer](t *testing.T, f *FlowField[T]) {
+	t.Helper()
+	for i, cost := range f.Integration {
+		x, y := f.tile(i)
+		best := math.Inf(1)
+		for _, g := range f.Goals {
+			if p, ok := FindPath(f.Collider, [2]int{x, y}, g, &f.Options); ok {
+				best = min(best, p.Cost)
+			}
+		}
+		if math.Abs(cost-best) > 1e-9 && !(math.IsInf(cost, 1) && math.IsInf(best, 1)) {
+			t.Fatalf("tile %d,%d: cost %v, cheapest path %v", x, y, cost, best)
+		}
+		if f.Next[i] < 0 {
+			if cost != 0 && !math.IsInf(cost, 1) {
+				t.Fatalf("tile %d,%d: no flow with cost %v", x, y, cost)
+			}
+			continue
+		}
+		nx, ny := f.tile(f.Next[i])
+		step := math.Inf(1)
+		forEachNeighbor(f.Collider, &f.Options, [2]int{x, y}, func(next [2]int, c float64) {
+			if next == [2]int{nx, ny} {
+				step = c
+			}
+		})
+		if math.Abs(cost-f.Integration[f.Next[i]]-step) > 1e-9 {
+			t.Fatalf("tile %d,%d: cost %v, next %d,%d costs %v with step %v", x, y, cost, nx, ny, f.Integration[f.Next[i]], step)
+		}
+	}
+}
+
+func TestFlowField(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for range 50 {
+		w, h := 4+r.Intn(10), 4+r.Intn(10)
+		c, opts := randCostCollider(r, w, h)
+		f := must(NewFlowField(c, [][2]int{{r.Intn(w), r.Intn(h)}, {r.Intn(w), r.Intn(h)}}, opts))
+		checkFlowField(t, f)
+	}
+}
+
+func TestFlowFieldUpdate(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for i := range 300 {
+		w, h := 5+r.Intn(20), 5+r.Intn(20)
+		c, opts := randCostCollider(r, w, h)
+		goals := [][2]int{{r.Intn(w), r.Intn(h)}, {r.Intn(w), r.Intn(h)}}
+		f := must(NewFlowField(c, goals, opts))
+		watcher := c.Watch(nil)
+		for step := range 5 {
+			for range 1 + r.Intn(3) {
+				c.FillRect(r.Intn(w), r.Intn(h), 1+r.Intn(3), 1+r.Intn(3), r.Intn(3))
+			}
+			if err := f.Update(watcher.Drain()); err != nil {
+				t.Fatal(err)
+			}
+			want := must(NewFlowField(c, goals, opts))
+			for j := range want.Integration {
+				if a, b := f.Integration[j], want.Integration[j]; a != b && math.Abs(a-b) > 1e-9 {
+					x, y := f.tile(j)
+					t.Fatalf("case %d step %d: tile %d,%d: updated cost %v, recomputed %v", i, step, x, y, a, b)
+				}
+			}
+		}
+		if i%20 == 0 {
+			checkFlowField(t, f)
+		}
+	}
+}
+
+func TestFlowFieldDirection(t *testing.T) {
+	c, err := tilecollider.NewColliderASCII[uint8](`
+		.....
+		.###.
+		.....`, nil, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := must(NewFlowField(c, [][2]int{{4, 2}}, &Options[uint8]{Diagonal: true}))
+	tests := []struct {
+		x, y   float64
+		dx, dy float64
+		ok     bool
+	}{
+		{8, 40, 1, 0, true},   // Bottom row: straight right
+		{24, 8, 1, 0, true},   // Above the wall: no corner cutting, so right
+		{8, 24, 0, 1, true},   // Left of the wall: down, the diagonal would cut the corner
+		{72, 40, 0, 0, true},  // At the goal
+		{24, 24, 0, 0, false}, // Solid tile
+		{-1, 8, 0, 0, false},  // Outside the map
+	}
+	for _, tt := range tests {
+		dx, dy, ok := f.Direction(tt.x, tt.y)
+		if ok != tt.ok || math.Abs(dx-tt.dx) > 1e-9 || math.Abs(dy-tt.dy) > 1e-9 {
+			t.Errorf("Direction(%v, %v) = %v, %v, %v, want %v, %v, %v", tt.x, tt.y, dx, dy, ok, tt.dx, tt.dy, tt.ok)
+		}
+	}
+	open := must(NewFlowField(tilecollider.NewCollider([][]uint8{{0, 0}, {0, 0}}, 16, 16), [][2]int{{1, 1}}, &Options[uint8]{Diagonal: true}))
+	if dx, dy, ok := open.Direction(8, 8); !ok || math.Abs(dx-math.Sqrt2/2) > 1e-9 || math.Abs(dy-math.Sqrt2/2) > 1e-9 {
+		t.Errorf("diagonal Direction = %v, %v, %v", dx, dy, ok)
+	}
+	if got := f.Cost([2]int{0, 2}); got != 4 {
+		t.Errorf("Cost = %v, want 4", got)
+	}
+	if got := f.Cost([2]int{9, 9}); !math.IsInf(got, 1) {
+		t.Errorf("Cost outside = %v, want +Inf", got)
+	}
+}
@@ -0,0 +1,520 @@
+package pathfind
+
+import (
+	"container/heap"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// maxEntranceWidth is the length of a border opening from which two transitions are placed at its ends instead of one in the middle
+const maxEntranceWidth = 6
+
+// Hierarchy is a hierarchical (HPA*) search graph over a bounded tilemap.
+//
+// The map is partitioned into square clusters. Transitions are placed on walkable openings between neighboring clusters
+// and the entrance tiles of each cluster are connected by the cost of the cheapest path inside the cluster.
+// Paths are searched on this abstract graph and refined to tiles on demand.
+// Paths are near-optimal since moves across cluster borders are orthogonal and paths stay inside clusters between entrances.
+type Hierarchy[T tilecollider.Integer] struct {
+	Collider    *tilecollider.Collider[T]
+	Options     Options[T] // MaxNodes and Region are ignored
+	ClusterSize int        // Width and height of the clusters in tiles
+
+	bounds   [4]int               // minX, minY, width, height
+	clusters [2]int               // columns, rows
+	borders  [][2][][2][2]int     // Transitions on the right and bottom border of each cluster
+	nodes    map[[2]int]*entrance // Entrance tiles
+}
+
+// entrance is a tile of the abstract graph
+type entrance struct {
+	refs  int // Number of transitions using the tile
+	edges []abstractEdge
+}
+
+type abstractEdge struct {
+	to   [2]int
+	cost float64
+}
+
+// NewHierarchy creates and builds a hierarchy with clusters of size by size tiles. opts may be nil.
+// It returns ErrClusterSize if size is not positive and ErrUnbounded if the collider's tile source is unbounded.
+func NewHierarchy[T tilecollider.Integer](c *tilecollider.Collider[T], size int, opts *Options[T]) (*Hierarchy[T], error) {
+	h := &Hierarchy[T]{Collider: c, ClusterSize: size}
+	if opts != nil {
+		h.Options = *opts
+	}
+	if err := h.Build(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// Build rebuilds the whole abstract graph
+func (h *Hierarchy[T]) Build() error {
+	if h.ClusterSize <= 0 {
+		return fmt.Errorf("%w: %d", ErrClusterSize, h.ClusterSize)
+	}
+	minX, minY, maxX, maxY, err := boundedBounds(h.Collider, "Hierarchy")
+	if err != nil {
+		return err
+	}
+	h.bounds = [4]int{minX, minY, maxX - minX, maxY - minY}
+	size := h.ClusterSize
+	h.clusters = [2]int{(h.bounds[2] + size - 1) / size, (h.bounds[3] + size - 1) / size}
+	h.borders = make([][2][][2][2]int, h.clusters[0]*h.clusters[1])
+	h.nodes = make(map[[2]int]*entrance)
+	for i := range h.borders {
+		h.scanBorder(i, 0)
+		h.scanBorder(i, 1)
+	}
+	for i := range h.borders {
+		h.connectCluster(i)
+	}
+	return nil
+}
+
+// Update updates the abstract graph after the tiles in the dirty regions changed.
+// Regions are X,Y,W,H rectangles in tile coordinates, e.g. from tilecollider.TileWatcher.Drain.
+// Only the clusters touching a dirty region and their neighbors are rebuilt.
+// If Options.Clearance is set, it must be updated first. If the map bounds changed, the whole graph is rebuilt (see Build).
+func (h *Hierarchy[T]) Update(dirty [][4]int) error {
+	if len(dirty) == 0 {
+		return nil
+	}
+	minX, minY, maxX, maxY := h.Collider.Bounds()
+	if [4]int{minX, minY, maxX - minX, maxY - minY} != h.bounds {
+		return h.Build()
+	}
+
+	// Regions are grown like in FlowField.Update
+	changed := make(map[int]bool)
+	grow := max(h.Options.AgentSize, 1)
+	for _, r := range dirty {
+		x0, y0 := max(r[0]-grow, minX), max(r[1]-grow, minY)
+		x1, y1 := min(r[0]+r[2]+1, maxX), min(r[1]+r[3]+1, maxY)
+		if x0 >= x1 || y0 >= y1 {
+			continue
+		}
+		c0, c1 := h.clusterOf([2]int{x0, y0}), h.clusterOf([2]int{x1 - 1, y1 - 1})
+		for cy := c0[1]; cy <= c1[1]; cy++ {
+			for cx := c0[0]; cx <= c1[0]; cx++ {
+				changed[cy*h.clusters[0]+cx] = true
+			}
+		}
+	}
+
+	// Borders are keyed by cluster*2+side. Keys are sorted so that edges are always added in the same order.
+	borders := make(map[int]bool)
+	connect := make(map[int]bool)
+	for i := range changed {
+		cx, cy := i%h.clusters[0], i/h.clusters[0]
+		borders[i*2] = true
+		borders[i*2+1] = true
+		connect[i] = true
+		if cx > 0 {
+			borders[(i-1)*2] = true
+			connect[i-1] = true
+		}
+		if cy > 0 {
+			borders[(i-h.clusters[0])*2+1] = true
+			connect[i-h.clusters[0]] = true
+		}
+		if cx+1 < h.clusters[0] {
+			connect[i+1] = true
+		}
+		if cy+1 < h.clusters[1] {
+			connect[i+h.clusters[0]] = true
+		}
+	}
+	sorted := slices.Sorted(maps.Keys(borders))
+	for _, b := range sorted {
+		h.removeBorder(b/2, b%2)
+	}
+	for _, b := range sorted {
+		h.scanBorder(b/2, b%2)
+	}
+	for _, i := range slices.Sorted(maps.Keys(connect)) {
+		h.connectCluster(i)
+	}
+	return nil
+}
+
+// Entrances returns the number of tiles in the abstract graph
+func (h *Hierarchy[T]) Entrances() int {
+	return len(h.nodes)
+}
+
+// FindPath returns a path between two tiles by searching the abstract graph and refining every segment.
+// ok is false if the goal is unreachable.
+func (h *Hierarchy[T]) FindPath(start, goal [2]int) (path Path, ok bool) {
+	abstract, ok := h.AbstractPath(start, goal)
+	if !ok {
+		return path, false
+	}
+	path.Tiles = [][2]int{start}
+	for i := 1; i < len(abstract.Tiles); i++ {
+		segment, ok := h.Refine(abstract.Tiles[i-1], abstract.Tiles[i])
+		if !ok {
+			return Path{}, false
+		}
+		path.Tiles = append(path.Tiles, segment.Tiles[1:]...)
+		path.Cost += segment.Cost
+	}
+	path.World = make([][2]float64, len(path.Tiles))
+	for i, t := range path.Tiles {
+		path.World[i] = AgentToWorld(h.Collider, t, h.Options.AgentSize)
+	}
+	return path, true
+}
+
+// AbstractPath returns the entrance tiles a path between two tiles passes through, with start and goal included.
+// Consecutive tiles are either in the same cluster or neighbors across a cluster border. Use Refine to get the tiles between them.
+func (h *Hierarchy[T]) AbstractPath(start, goal [2]int) (path Path, ok bool) {
+	opts := h.Options
+	opts.Region = [4]int{}
+	opts.MaxNodes = 0
+	if _, walkable := tileCost(h.Collider, &opts, start[0], start[1]); !walkable {
+		return path, false
+	}
+	if _, walkable := tileCost(h.Collider, &opts, goal[0], goal[1]); !walkable {
+		return path, false
+	}
+
+	startCluster, goalCluster := h.clusterIndex(start), h.clusterIndex(goal)
+	startRegion, goalRegion := h.clusterRect(startCluster), h.clusterRect(goalCluster)
+	opts.Region = startRegion
+	fromStart := newRegionSearch(h.Collider, &opts).run(start, false)
+	opts.Region = goalRegion
+	toGoal := newRegionSearch(h.Collider, &opts).run(goal, true)
+	startNodes := h.clusterNodes(startCluster)
+
+	neighbors := func(p [2]int, fn func(next [2]int, cost float64)) {
+		if e := h.nodes[p]; e != nil {
+			for _, edge := range e.edges {
+				fn(edge.to, edge.cost)
+			}
+			if h.clusterIndex(p) == goalCluster {
+				if cost := toGoal[regionIndex(goalRegion, p)]; !math.IsInf(cost, 1) {
+					fn(goal, cost)
+				}
+			}
+		}
+		if p != start {
+			return
+		}
+		for _, n := range startNodes {
+			if cost := fromStart[regionIndex(startRegion, n)]; !math.IsInf(cost, 1) {
+				fn(n, cost)
+			}
+		}
+		if startCluster == goalCluster {
+			if cost := fromStart[regionIndex(startRegion, goal)]; !math.IsInf(cost, 1) {
+				fn(goal, cost)
+			}
+		}
+	}
+
+	nodes := map[[2]int]*node{start: {pos: start, index: -1}}
+	open := &openList{}
+	heap.Push(open, nodes[start])
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		current.closed = true
+		if current.pos == goal {
+			return buildPath(h.Collider, current, opts.AgentSize), true
+		}
+		neighbors(current.pos, func(next [2]int, stepCost float64) {
+			g := current.g + stepCost
+			n, seen := nodes[next]
+			if seen && (n.closed || g >= n.g) {
+				return
+			}
+			if !seen {
+				n = &node{pos: next, index: -1}
+				nodes[next] = n
+			}
+			n.g = g
+			n.f = g + heuristic(next, goal, opts.Diagonal)
+			n.parent = current
+			if n.index < 0 {
+				heap.Push(open, n)
+			} else {
+				heap.Fix(open, n.index)
+			}
+		})
+	}
+	return path, false
+}
+
+// Refine returns the cheapest path between two consecutive tiles of an abstract path.
+// ok is false if the tiles are neither in the same cluster nor neighbors across a cluster border, or the path is blocked.
+func (h *Hierarchy[T]) Refine(from, to [2]int) (path Path, ok bool) {
+	opts := h.Options
+	opts.Region = [4]int{}
+	opts.MaxNodes = 0
+	if cluster := h.clusterIndex(from); cluster == h.clusterIndex(to) {
+		opts.Region = h.clusterRect(cluster)
+		return FindPath(h.Collider, from, to, &opts)
+	}
+	if math.Abs(float64(from[0]-to[0]))+math.Abs(float64(from[1]-to[1])) != 1 {
+		return path, false
+	}
+	if _, walkable := tileCost(h.Collider, &opts, from[0], from[1]); !walkable {
+		return path, false
+	}
+	cost, walkable := tileCost(h.Collider, &opts, to[0], to[1])
+	if !walkable {
+		return path, false
+	}
+	return Path{
+		Tiles: [][2]int{from, to},
+		World: [][2]float64{AgentToWorld(h.Collider, from, opts.AgentSize), AgentToWorld(h.Collider, to, opts.AgentSize)},
+		Cost:  cost,
+	}, true
+}
+
+// scanBorder places transitions on the right (side 0) or bottom (side 1) border of the cluster
+func (h *Hierarchy[T]) scanBorder(cluster, side int) {
+	cx, cy := cluster%h.clusters[0], cluster/h.clusters[0]
+	if (side == 0 && cx+1 >= h.clusters[0]) || (side == 1 && cy+1 >= h.clusters[1]) {
+		return
+	}
+	opts := h.Options
+	opts.Region = [4]int{}
+	r := h.clusterRect(cluster)
+
+	// Pairs of tiles across the border, inside first
+	var length int
+	var pair func(i int) [2][2]int
+	if side == 0 {
+		length = r[3]
+		pair = func(i int) [2][2]int { return [2][2]int{{r[0] + r[2] - 1, r[1] + i}, {r[0] + r[2], r[1] + i}} }
+	} else {
+		length = r[2]
+		pair = func(i int) [2][2]int { return [2][2]int{{r[0] + i, r[1] + r[3] - 1}, {r[0] + i, r[1] + r[3]}} }
+	}
+
+	var transitions [][2][2]int
+	runStart := -1
+	for i := 0; i <= length; i++ {
+		open := false
+		if i < length {
+			p := pair(i)
+			_, a := tileCost(h.Collider, &opts, p[0][0], p[0][1])
+			_, b := tileCost(h.Collider, &opts, p[1][0], p[1][1])
+			open = a && b
+		}
+		if open && runStart < 0 {
+			runStart = i
+		}
+		if open || runStart < 0 {
+			continue
+		}
+		if i-runStart < maxEntranceWidth {
+			transitions = append(transitions, pair((runStart+i-1)/2))
+		} else {
+			transitions = append(transitions, pair(runStart), pair(i-1))
+		}
+		runStart = -1
+	}
+
+	for _, t := range transitions {
+		for k := range 2 {
+			from, to := t[k], t[1-k]
+			e := h.nodes[from]
+			if e == nil {
+				e = &entrance{}
+				h.nodes[from] = e
+			}
+			e.refs++
+			cost, _ := tileCost(h.Collider, &opts, to[0], to[1])
+			e.setEdge(to, cost)
+		}
+	}
+	h.borders[cluster][side] = transitions
+}
+
+// removeBorder removes the transitions of the border and the entrances no longer used
+func (h *Hierarchy[T]) removeBorder(cluster, side int) {
+	for _, t := range h.borders[cluster][side] {
+		for k := range 2 {
+			e := h.nodes[t[k]]
+			e.removeEdges(func(to [2]int) bool { return to == t[1-k] })
+			if e.refs--; e.refs == 0 {
+				delete(h.nodes, t[k])
+			}
+		}
+	}
+	h.borders[cluster][side] = nil
+}
+
+// connectCluster recomputes the edges between the entrances of the cluster
+func (h *Hierarchy[T]) connectCluster(cluster int) {
+	opts := h.Options
+	opts.Region = h.clusterRect(cluster)
+	entrances := h.clusterNodes(cluster)
+	for _, p := range entrances {
+		h.nodes[p].removeEdges(func(to [2]int) bool { return h.clusterIndex(to) == cluster })
+	}
+	if len(entrances) == 0 {
+		return
+	}
+	search := newRegionSearch(h.Collider, &opts)
+	for _, p := range entrances {
+		costs := search.run(p, false)
+		e := h.nodes[p]
+		for _, q := range entrances {
+			if cost := costs[regionIndex(opts.Region, q)]; q != p && !math.IsInf(cost, 1) {
+				e.setEdge(q, cost)
+			}
+		}
+	}
+}
+
+// clusterNodes returns the entrance tiles inside the cluster
+func (h *Hierarchy[T]) clusterNodes(cluster int) [][2]int {
+	var tiles [][2]int
+	add := func(transitions [][2][2]int, k int) {
+		for _, t := range transitions {
+			if !slices.Contains(tiles, t[k]) {
+				tiles = append(tiles, t[k])
+			}
+		}
+	}
+	add(h.borders[cluster][0], 0)
+	add(h.borders[cluster][1], 0)
+	if cluster%h.clusters[0] > 0 {
+		add(h.borders[cluster-1][0], 1)
+	}
+	if cluster >= h.clusters[0] {
+		add(h.borders[cluster-h.clusters[0]][1], 1)
+	}
+	return tiles
+}
+
+// clusterOf returns the column and row of the cluster containing the tile
+func (h *Hierarchy[T]) clusterOf(tile [2]int) [2]int {
+	return [2]int{(tile[0] - h.bounds[0]) / h.ClusterSize, (tile[1] - h.bounds[1]) / h.ClusterSize}
+}
+
+func (h *Hierarchy[T]) clusterIndex(tile [2]int) int {
+	c := h.clusterOf(tile)
+	return c[1]*h.clusters[0] + c[0]
+}
+
+// clusterRect returns the X,Y,W,H rectangle of the cluster in tile coordinates
+func (h *Hierarchy[T]) clusterRect(cluster int) [4]int {
+	x := cluster % h.clusters[0] * h.ClusterSize
+	y := cluster / h.clusters[0] * h.ClusterSize
+	return [4]int{
+		h.bounds[0] + x,
+		h.bounds[1] + y,
+		min(h.ClusterSize, h.bounds[2]-x),
+		min(h.ClusterSize, h.bounds[3]-y),
+	}
+}
+
+func (e *entrance) setEdge(to [2]int, cost float64) {
+	for i := range e.edges {
+		if e.edges[i].to == to {
+			e.edges[i].cost = cost
+			return
+		}
+	}
+	e.edges = append(e.edges, abstractEdge{to, cost})
+}
+
+func (e *entrance) removeEdges(remove func(to [2]int) bool) {
+	e.edges = slices.DeleteFunc(e.edges, func(edge abstractEdge) bool { return remove(edge.to) })
+}
+
+// regionSearch runs Dijkstra inside a rectangle using the cached cost of entering each of its tiles
+type regionSearch struct {
+	region   [4]int
+	enter    []float64 // Cost of entering each tile, +Inf if blocked
+	diagonal bool
+	costs    []float64
+	queue    flowQueue
+}
+
+// newRegionSearch caches the tile costs of opts.Region
+func newRegionSearch[T tilecollider.Integer](c *tilecollider.Collider[T], opts *Options[T]) *regionSearch {
+	r := opts.Region
+	s := &regionSearch{region: r, diagonal: opts.Diagonal, enter: make([]float64, r[2]*r[3]), costs: make([]float64, r[2]*r[3])}
+	for i := range s.enter {
+		cost, ok := tileCost(c, opts, i%r[2]+r[0], i/r[2]+r[1])
+		if !ok {
+			cost = math.Inf(1)
+		}
+		s.enter[i] = cost
+	}
+	return s
+}
+
+// run returns the cost from the source to every tile of the region in row-major order.
+// If reverse is set, it returns the cost from every tile to the source instead.
+// The result is overwritten by the next run.
+func (s *regionSearch) run(source [2]int, reverse bool) []float64 {
+	for i := range s.costs {
+		s.costs[i] = math.Inf(1)
+	}
+	start := regionIndex(s.region, source)
+	if math.IsInf(s.enter[start], 1) {
+		return s.costs
+	}
+	s.costs[start] = 0
+	s.queue = append(s.queue[:0], flowItem{start, 0})
+	w, h := s.region[2], s.region[3]
+
+	for s.queue.Len() > 0 {
+		item := heap.Pop(&s.queue).(flowItem)
+		if item.cost > s.costs[item.index] {
+			continue
+		}
+		x, y := item.index%w, item.index/w
+		relax := func(d [2]int, factor float64) bool {
+			nx, ny := x+d[0], y+d[1]
+			if nx < 0 || ny < 0 || nx >= w || ny >= h {
+				return false
+			}
+			j := ny*w + nx
+			if math.IsInf(s.enter[j], 1) {
+				return false
+			}
+			// In reverse, the move is from the neighbor into this tile
+			step := s.enter[j]
+			if reverse {
+				step = s.enter[item.index]
+			}
+			if cost := item.cost + step*factor; cost < s.costs[j] {
+				s.costs[j] = cost
+				heap.Push(&s.queue, flowItem{j, cost})
+			}
+			return true
+		}
+		var open [4]bool
+		for i, d := range orthogonal {
+			open[i] = relax(d, 1)
+		}
+		if !s.diagonal {
+			continue
+		}
+		for i, d := range diagonal {
+			if open[i] && open[(i+1)%4] {
+				relax(d, math.Sqrt2)
+			}
+		}
+	}
+	return s.costs
+}
+
+// regionIndex returns the row-major index of the tile in the X,Y,W,H rectangle
+func regionIndex(r [4]int, tile [2]int) int {
+	return (tile[1]-r[1])*r[2] + tile[0] - r[0]
+}
@@ -0,0 +1,133 @@
+package pathfind
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// abstractGraph returns the edges of the hierarchy and fails if an entrance is unreferenced or an edge dangles
+func abstractGraph(t *testing.T, h *Hierarchy[int]) map[[2][2]int]float64 {
+	t.Helper()
+	g := make(map[[2][2]int]float64)
+	for p, e := range h.nodes {
+		if e.refs <= 0 {
+			t.Fatalf("entrance %v has %d references", p, e.refs)
+		}
+		for _, edge := range e.edges {
+			if _, ok := h.nodes[edge.to]; !ok {
+				t.Fatalf("edge %v -> %v leads to a removed entrance", p, edge.to)
+			}
+			g[[2][2]int{p, edge.to}] = edge.cost
+		}
+	}
+	return g
+}
+
+func TestHierarchy(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	worst := 1.0
+	for i := range 300 {
+		w, h := 5+r.Intn(40), 5+r.Intn(40)
+		m := make([][]int, h)
+		for y := range m {
+			m[y] = make([]int, w)
+			for x := range m[y] {
+				if r.Float64() < 0.2 {
+					m[y][x] = 1
+				} else if r.Float64() < 0.2 {
+					m[y][x] = 2
+				}
+			}
+		}
+		c := tilecollider.NewColliderSource[int](tilecollider.SliceSource[int](m), 8, 8)
+		c.SolidFunc = func(id int) bool { return id == 1 }
+		opts := &Options[int]{Diagonal: r.Intn(2) == 0, AgentSize: 1 + r.Intn(2), Cost: func(id, x, y int) float64 { return float64(1 + 2*(id/2)) }}
+		hier := must(NewHierarchy(c, 3+r.Intn(6), opts))
+		watcher := c.Watch(nil)
+		for step := range 5 {
+			if step > 0 {
+				for range 1 + r.Intn(3) {
+					c.FillRect(r.Intn(w), r.Intn(h), 1+r.Intn(3), 1+r.Intn(3), r.Intn(3))
+				}
+				if err := hier.Update(watcher.Drain()); err != nil {
+					t.Fatal(err)
+				}
+				// The updated graph must equal a rebuilt one
+				got, want := abstractGraph(t, hier), abstractGraph(t, must(NewHierarchy(c, hier.ClusterSize, opts)))
+				if len(got) != len(want) {
+					t.Fatalf("case %d step %d: %d edges, rebuilt graph has %d", i, step, len(got), len(want))
+				}
+				for k, v := range want {
+					if got[k] != v {
+						t.Fatalf("case %d step %d: edge %v costs %v, rebuilt %v", i, step, k, got[k], v)
+					}
+				}
+			}
+			for range 10 {
+				start, goal := [2]int{r.Intn(w), r.Intn(h)}, [2]int{r.Intn(w), r.Intn(h)}
+				optimal, ok1 := FindPath(c, start, goal, opts)
+				p, ok2 := hier.FindPath(start, goal)
+				if ok1 != ok2 {
+					t.Fatalf("case %d step %d: %v -> %v: ok = %v, A* found %v", i, step, start, goal, ok2, ok1)
+				}
+				if !ok1 {
+					continue
+				}
+				if p.Cost < optimal.Cost-1e-9 {
+					t.Fatalf("case %d step %d: cost %v is below the optimum %v", i, step, p.Cost, optimal.Cost)
+				}
+				// Short paths across a cluster border can detour through a distant transition
+				if optimal.Cost >= 20 {
+					worst = max(worst, p.Cost/optimal.Cost)
+				}
+				checkPath(t, c, p, start, goal, opts)
+				for k, tile := range p.Tiles {
+					if p.World[k] != AgentToWorld(c, tile, opts.AgentSize) {
+						t.Fatalf("World[%d] = %v, want the agent center at %v", k, p.World[k], tile)
+					}
+				}
+			}
+		}
+	}
+	// Long HPA* paths are near-optimal
+	if worst > 1.75 {
+		t.Errorf("worst cost ratio to the optimum is %v", worst)
+	}
+}
+
+func TestHierarchyAbstractPath(t *testing.T) {
+	m := make([][]int, 12)
+	for y := range m {
+		m[y] = make([]int, 12)
+	}
+	c := tilecollider.NewCollider(m, 16, 16)
+	h := must(NewHierarchy(c, 4, nil))
+	if h.Entrances() == 0 {
+		t.Fatal("no entrances")
+	}
+	start, goal := [2]int{0, 0}, [2]int{11, 11}
+	abstract, ok := h.AbstractPath(start, goal)
+	if !ok || abstract.Tiles[0] != start || abstract.Tiles[len(abstract.Tiles)-1] != goal {
+		t.Fatalf("AbstractPath = %v, %v", abstract.Tiles, ok)
+	}
+	// Refining every segment gives the path of FindPath
+	cost := 0.0
+	for i := 1; i < len(abstract.Tiles); i++ {
+		segment, ok := h.Refine(abstract.Tiles[i-1], abstract.Tiles[i])
+		if !ok {
+			t.Fatalf("Refine(%v, %v) failed", abstract.Tiles[i-1], abstract.Tiles[i])
+		}
+		cost += segment.Cost
+	}
+	p, ok := h.FindPath(start, goal)
+	if !ok || math.Abs(p.Cost-cost) > 1e-9 || p.Cost != 22 {
+		t.Errorf("FindPath cost %v, refined segments %v, want 22", p.Cost, cost)
+	}
+	// Tiles that are neither in one cluster nor neighbors across a border cannot be refined
+	if _, ok := h.Refine([2]int{0, 0}, [2]int{5, 5}); ok {
+		t.Error("Refine across clusters succeeded")
+	}
+}
@@ -0,0 +1,306 @@
+package pathfind
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// EdgeKind is the kind of movement of a navigation graph edge
+type EdgeKind int
+
+const (
+	Walk EdgeKind = iota // Walk to the adjacent surface tile
+	Drop                 // Walk off a ledge and fall
+	Jump                 // Jump and land on another surface tile
+)
+
+func (k EdgeKind) String() string {
+	switch k {
+	case Walk:
+		return "walk"
+	case Drop:
+		return "drop"
+	case Jump:
+		return "jump"
+	}
+	return "unknown"
+}
+
+// NavConfig describes the movement of a platformer agent.
+//
+// Arcs are simulated like a typical game loop: every tick VelY is increased by Gravity (clamped to MaxFallSpeed),
+// then Collide is called with VelX and VelY. Speeds are in pixels per tick.
+type NavConfig struct {
+	AgentSize    [2]float64 // Width and height of the agent rectangle in pixels
+	RunSpeed     float64    // Horizontal speed
+	JumpSpeed    float64    // Initial upward speed of jumps. If zero, no jump edges are built.
+	Gravity      float64    // Downward acceleration
+	MaxFallSpeed float64    // Maximum downward speed. If zero, falling speed is not limited.
+	MaxTicks     int        // Maximum simulated ticks per arc. Defaults to 300.
+}
+
+// NavEdge is a movement between two surface tiles of a navigation graph
+type NavEdge struct {
+	To   int      // Index of the target node
+	Kind EdgeKind // Kind of movement
+	VelX float64  // Horizontal speed to hold during the movement
+	Cost float64  // Duration of the movement in ticks
+}
+
+// NavGraph is a navigation graph of the walkable surfaces of a tilemap for a platformer agent.
+//
+// A node is an empty tile directly above a solid tile where the agent fits.
+type NavGraph struct {
+	Nodes [][2]int    // X,Y tile coordinates of the surface tiles
+	Edges [][]NavEdge // Outgoing edges of each node
+
+	index map[[2]int]int
+}
+
+// NavStep is a step of a navigation path
+type NavStep struct {
+	Tile [2]int   // Tile reached by the step
+	Kind EdgeKind // Movement used to reach the tile
+	VelX float64  // Horizontal speed to hold during the movement
+}
+
+// BuildNavGraph builds the navigation graph of the collider's tilemap.
+// Drop and jump edges are validated by simulating the arcs with Collide on a copy of the collider.
+// It returns ErrUnbounded if the collider's tile source is unbounded.
+func BuildNavGraph[T tilecollider.Integer](c *tilecollider.Collider[T], cfg NavConfig) (*NavGraph, error) {
+	if cfg.MaxTicks <= 0 {
+		cfg.MaxTicks = 300
+	}
+	minX, minY, maxX, maxY, err := boundedBounds(c, "BuildNavGraph")
+	if err != nil {
+		return nil, err
+	}
+	g := &NavGraph{index: make(map[[2]int]int)}
+	for y := minY; y < maxY; y++ {
+		for x := minX; x < maxX; x++ {
+			if isSurface(c, cfg, x, y) {
+				g.index[[2]int{x, y}] = len(g.Nodes)
+				g.Nodes = append(g.Nodes, [2]int{x, y})
+			}
+		}
+	}
+	g.Edges = make([][]NavEdge, len(g.Nodes))
+
+	sim := *c
+	sim.Collisions = nil
+	walkCost := float64(c.TileSize[0]) / cfg.RunSpeed
+
+	for from, tile := range g.Nodes {
+		best := make(map[int]NavEdge)
+		add := func(e NavEdge) {
+			if e.To == from {
+				return
+			}
+			if old, ok := best[e.To]; !ok || e.Cost < old.Cost {
+				best[e.To] = e
+			}
+		}
+
+		for _, dir := range [2]float64{-1, 1} {
+			next := [2]int{tile[0] + int(dir), tile[1]}
+			if to, ok := g.index[next]; ok {
+				add(NavEdge{To: to, Kind: Walk, VelX: dir * cfg.RunSpeed, Cost: walkCost})
+			} else if id, ok := c.Tile(next[0], next[1]); ok && !c.IsSolid(id) {
+				if to, ticks, ok := simulateArc(g, &sim, cfg, tile, dir*cfg.RunSpeed, 0); ok {
+					add(NavEdge{To: to, Kind: Drop, VelX: dir * cfg.RunSpeed, Cost: float64(ticks)})
+				}
+			}
+			if cfg.JumpSpeed <= 0 {
+				continue
+			}
+			for _, f := range [...]float64{0.25, 0.5, 0.75, 1} {
+				velX := dir * f * cfg.RunSpeed
+				if to, ticks, ok := simulateArc(g, &sim, cfg, tile, velX, -cfg.JumpSpeed); ok {
+					add(NavEdge{To: to, Kind: Jump, VelX: velX, Cost: float64(ticks)})
+				}
+			}
+		}
+		if cfg.JumpSpeed > 0 {
+			if to, ticks, ok := simulateArc(g, &sim, cfg, tile, 0, -cfg.JumpSpeed); ok {
+				add(NavEdge{To: to, Kind: Jump, Cost: float64(ticks)})
+			}
+		}
+
+		for to := range best {
+			g.Edges[from] = append(g.Edges[from], best[to])
+		}
+		sortEdges(g.Edges[from])
+	}
+	return g, nil
+}
+
+// Node returns the index of the node of the surface tile
+func (g *NavGraph) Node(tile [2]int) (int, bool) {
+	i, ok := g.index[tile]
+	return i, ok
+}
+
+// FindPath returns the fastest sequence of steps between two surface tiles.
+// The first step is the start tile. ok is false if the goal is unreachable.
+func (g *NavGraph) FindPath(start, goal [2]int) (steps []NavStep, ok bool) {
+	from, ok1 := g.index[start]
+	to, ok2 := g.index[goal]
+	if !ok1 || !ok2 {
+		return nil, false
+	}
+
+	dist := make([]float64, len(g.Nodes))
+	prev := make([]int, len(g.Nodes))
+	via := make([]NavEdge, len(g.Nodes))
+	for i := range dist {
+		dist[i] = math.Inf(1)
+		prev[i] = -1
+	}
+	dist[from] = 0
+	queue := &navQueue{{node: from}}
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(navItem)
+		if item.dist > dist[item.node] {
+			continue
+		}
+		if item.node == to {
+			break
+		}
+		for _, e := range g.Edges[item.node] {
+			if d := item.dist + e.Cost; d < dist[e.To] {
+				dist[e.To] = d
+				prev[e.To] = item.node
+				via[e.To] = e
+				heap.Push(queue, navItem{node: e.To, dist: d})
+			}
+		}
+	}
+	if math.IsInf(dist[to], 1) {
+		return nil, false
+	}
+
+	for n := to; n != from; n = prev[n] {
+		steps = append(steps, NavStep{Tile: g.Nodes[n], Kind: via[n].Kind, VelX: via[n].VelX})
+	}
+	steps = append(steps, NavStep{Tile: g.Nodes[from], Kind: Walk})
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	return steps, true
+}
+
+// simulateArc moves the agent from the surface tile with the initial velocity until it lands on another surface tile.
+// It returns the landing node and the number of ticks.
+func simulateArc[T tilecollider.Integer](g *NavGraph, c *tilecollider.Collider[T], cfg NavConfig, tile [2]int, velX, velY float64) (to, ticks int, ok bool) {
+	tw, th := float64(c.TileSize[0]), float64(c.TileSize[1])
+	w, h := cfg.AgentSize[0], cfg.AgentSize[1]
+	x, y := standPosition(c, cfg, tile)
+	_, minY, _, maxY := c.Bounds()
+	airborne := false
+
+	for ticks = 1; ticks <= cfg.MaxTicks; ticks++ {
+		velY += cfg.Gravity
+		if cfg.MaxFallSpeed > 0 {
+			velY = min(velY, cfg.MaxFallSpeed)
+		}
+		dx, dy := c.Collide(x, y, w, h, velX, velY, nil)
+		x, y = x+dx, y+dy
+
+		grounded := false
+		for _, col := range c.Collisions {
+			if col.Normal[1] == -1 {
+				grounded = true
+			}
+			if col.Normal[1] == 1 {
+				velY = 0
+			}
+		}
+		if !grounded {
+			airborne = true
+			if y > float64(maxY)*th || y+h < float64(minY)*th {
+				return 0, 0, false
+			}
+			continue
+		}
+		velY = 0
+		if !airborne {
+			continue
+		}
+
+		// Landed: find the surface tile under the center of the agent, or else under any column it covers
+		row := int(math.Floor((y + h - th/2) / th))
+		if n, ok := g.index[[2]int{int(math.Floor((x + w/2) / tw)), row}]; ok {
+			return n, ticks, true
+		}
+		left, right := agentColumns(c, x, w)
+		for col := left; col <= right; col++ {
+			if n, ok := g.index[[2]int{col, row}]; ok {
+				return n, ticks, true
+			}
+		}
+		return 0, 0, false
+	}
+	return 0, 0, false
+}
+
+// isSurface reports whether the tile is empty, the tile below it is solid and the agent standing on it
+// fits in every tile column its width covers
+func isSurface[T tilecollider.Integer](c *tilecollider.Collider[T], cfg NavConfig, x, y int) bool {
+	if id, ok := c.Tile(x, y+1); !ok || !c.IsSolid(id) {
+		return false
+	}
+	_, minY, _, _ := c.Bounds()
+	height := max(1, int(math.Ceil(cfg.AgentSize[1]/float64(c.TileSize[1]))))
+	ax, _ := standPosition(c, cfg, [2]int{x, y})
+	left, right := agentColumns(c, ax, cfg.AgentSize[0])
+	// Rows above the map are open space
+	for ty := max(y-height+1, minY); ty <= y; ty++ {
+		for tx := left; tx <= right; tx++ {
+			if id, ok := c.Tile(tx, ty); !ok || c.IsSolid(id) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// standPosition returns the top-left position of the agent standing centered on the surface tile
+func standPosition[T tilecollider.Integer](c *tilecollider.Collider[T], cfg NavConfig, tile [2]int) (x, y float64) {
+	tw, th := float64(c.TileSize[0]), float64(c.TileSize[1])
+	return float64(tile[0])*tw + (tw-cfg.AgentSize[0])/2, float64(tile[1]+1)*th - cfg.AgentSize[1]
+}
+
+// agentColumns returns the inclusive range of tile columns covered by an agent of width w at x
+func agentColumns[T tilecollider.Integer](c *tilecollider.Collider[T], x, w float64) (left, right int) {
+	tw := float64(c.TileSize[0])
+	left = int(math.Floor(x / tw))
+	right = max(left, int(math.Ceil((x+w)/tw))-1)
+	return left, right
+}
+
+// sortEdges sorts edges by target node for deterministic graphs
+func sortEdges(edges []NavEdge) {
+	slices.SortFunc(edges, func(a, b NavEdge) int { return a.To - b.To })
+}
+
+type navItem struct {
+	node int
+	dist float64
+}
+
+// navQueue is a min-heap of nodes ordered by distance
+type navQueue []navItem
+
+func (q navQueue) Len() int           { return len(q) }
+func (q navQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+func (q navQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *navQueue) Push(x any)        { *q = append(*q, x.(navItem)) }
+func (q *navQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,149 @@
+package pathfind
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+const navLevel = `
+	#..........#
+	#..........#
+	#.......####
+	#..###.....#
+	#..........#
+	#...........
+	######..####
+	######..####
+	############`
+
+var navConfig = NavConfig{AgentSize: [2]float64{12, 14}, RunSpeed: 2, JumpSpeed: 6.5, Gravity: 0.3, MaxFallSpeed: 8}
+
+func newNavLevel(t *testing.T, s string) *tilecollider.Collider[uint8] {
+	t.Helper()
+	c, err := tilecollider.NewColliderASCII[uint8](s, nil, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// checkSteps fails if consecutive steps are not connected by an edge of the same kind and speed
+func checkSteps(t *testing.T, g *NavGraph, steps []NavStep) {
+	t.Helper()
+	for i := 1; i < len(steps); i++ {
+		from, _ := g.Node(steps[i-1].Tile)
+		to, _ := g.Node(steps[i].Tile)
+		if !slices.ContainsFunc(g.Edges[from], func(e NavEdge) bool {
+			return e.To == to && e.Kind == steps[i].Kind && e.VelX == steps[i].VelX
+		}) {
+			t.Fatalf("step %d %v has no edge from %v", i, steps[i], steps[i-1].Tile)
+		}
+	}
+}
+
+func kinds(steps []NavStep) []EdgeKind {
+	var k []EdgeKind
+	for _, s := range steps[1:] {
+		k = append(k, s.Kind)
+	}
+	return k
+}
+
+func TestNavGraphPaths(t *testing.T) {
+	c := newNavLevel(t, navLevel)
+	highJump := navConfig
+	highJump.JumpSpeed = 8
+	noJump := navConfig
+	noJump.JumpSpeed = 0
+	graphs := map[string]*NavGraph{
+		"default":   must(BuildNavGraph(c, navConfig)),
+		"high jump": must(BuildNavGraph(c, highJump)),
+		"no jump":   must(BuildNavGraph(c, noJump)),
+	}
+	tests := []struct {
+		name        string
+		graph       string
+		start, goal [2]int
+		ok          bool
+		want        EdgeKind // Required kind of movement on the path
+	}{
+		{"jump onto block", "default", [2]int{1, 5}, [2]int{4, 2}, true, Jump},
+		{"drop from block", "default", [2]int{3, 2}, [2]int{1, 5}, true, Drop},
+		{"drop into pit", "default", [2]int{8, 5}, [2]int{6, 7}, true, Drop},
+		{"jump out of pit", "default", [2]int{6, 7}, [2]int{1, 5}, true, Jump},
+		{"platform too high", "default", [2]int{1, 5}, [2]int{10, 1}, false, 0},
+		{"jump onto platform", "high jump", [2]int{1, 5}, [2]int{10, 1}, true, Jump},
+		{"walk and drop", "no jump", [2]int{1, 5}, [2]int{6, 7}, true, Drop},
+		{"stuck in pit", "no jump", [2]int{6, 7}, [2]int{1, 5}, false, 0},
+		{"not a surface", "default", [2]int{1, 4}, [2]int{1, 5}, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := graphs[tt.graph]
+			steps, ok := g.FindPath(tt.start, tt.goal)
+			if ok != tt.ok {
+				t.Fatalf("ok = %v, want %v (steps %v)", ok, tt.ok, steps)
+			}
+			if !ok {
+				return
+			}
+			if steps[0].Tile != tt.start || steps[len(steps)-1].Tile != tt.goal {
+				t.Fatalf("steps %v do not go from %v to %v", steps, tt.start, tt.goal)
+			}
+			checkSteps(t, g, steps)
+			if !slices.Contains(kinds(steps), tt.want) {
+				t.Errorf("steps %v have no %v", steps, tt.want)
+			}
+		})
+	}
+}
+
+func TestNavGraphDeterministic(t *testing.T) {
+	c := newNavLevel(t, navLevel)
+	a, b := must(BuildNavGraph(c, navConfig)), must(BuildNavGraph(c, navConfig))
+	if !reflect.DeepEqual(a, b) {
+		t.Error("graphs differ")
+	}
+}
+
+func TestNavGraphWideAgent(t *testing.T) {
+	c := newNavLevel(t, `
+		#.....#
+		#.....#
+		###.###
+		#######`)
+	cfg := navConfig
+	// 1.5 tiles wide: centered on a tile it covers one column on each side
+	cfg.AgentSize[0] = 24
+	g := must(BuildNavGraph(c, cfg))
+	if want := [][2]int{{2, 1}, {4, 1}}; !reflect.DeepEqual(g.Nodes, want) {
+		t.Errorf("Nodes = %v, want %v", g.Nodes, want)
+	}
+	// The one-tile gap in the floor is too narrow for the agent
+	if _, ok := g.Node([2]int{3, 2}); ok {
+		t.Error("agent fits in the gap")
+	}
+}
+
+func TestNavGraphNodesFit(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := range 100 {
+		c := randCollider(r, 12, 10)
+		cfg := navConfig
+		cfg.AgentSize = [2]float64{4 + r.Float64()*40, 4 + r.Float64()*30}
+		cfg.JumpSpeed = 0
+		g := must(BuildNavGraph(c, cfg))
+		sim := *c
+		sim.StaticCheck = true
+		for _, n := range g.Nodes {
+			x, y := standPosition(c, cfg, n)
+			if dx, dy := sim.Collide(x, y, cfg.AgentSize[0], cfg.AgentSize[1], 0, 0, nil); dx != 0 || dy != 0 {
+				t.Fatalf("case %d: agent %v standing on %v overlaps %v", i, cfg.AgentSize, n, sim.Collisions)
+			}
+		}
+	}
+}
@@ -0,0 +1,255 @@
+// Package pathfind finds paths over the tile grid of a tilecollider.Collider using its solidity rules.
+package pathfind
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/setanarut/tilecollider"
+)
+
+var (
+	// ErrUnbounded is returned for colliders with an unbounded tile source such as tilecollider.ChunkMap.
+	// Use FindPath with MaxNodes or Region for such maps.
+	ErrUnbounded   = errors.New("pathfind: tile source is unbounded")
+	ErrClusterSize = errors.New("pathfind: cluster size must be positive")
+)
+
+// Options configures path searches
+type Options[T tilecollider.Integer] struct {
+	// Diagonal enables 8-connectivity. Diagonal moves never cut corners of solid tiles.
+	Diagonal bool
+	// Cost returns the cost of entering a walkable tile. Return math.Inf(1) to block the tile.
+	// Costs should be at least 1, otherwise the returned path may not be the cheapest. Defaults to 1.
+	Cost func(id T, x, y int) float64
+	// MaxNodes limits the number of expanded tiles. 0 means no limit. Set it for unbounded tile sources.
+	MaxNodes int
+	// AgentSize is the width and height of the agent in tiles (see AgentTiles). 0 and 1 mean a single tile.
+	// Tiles of the path are the top-left tiles of the agent and Cost is only evaluated for them.
+	AgentSize int
+	// Clearance speeds up searches with AgentSize greater than 1. It must be kept up to date with the tiles.
+	// If nil, the tiles under the agent are scanned.
+	Clearance *ClearanceMap[T]
+	// Region limits the search to the X,Y,W,H rectangle in tile coordinates. A zero width means no limit.
+	Region [4]int
+}
+
+// Path is a path over the tile grid
+type Path struct {
+	Tiles [][2]int     // X,Y tile coordinates from start to goal, both included
+	World [][2]float64 // Centers of the agent at each tile in world pixels (see AgentToWorld)
+	Cost  float64      // Total cost of the path
+}
+
+// FindPath returns the cheapest path between two tiles with A*.
+// ok is false if the goal is unreachable.
+func FindPath[T tilecollider.Integer](c *tilecollider.Collider[T], start, goal [2]int, opts *Options[T]) (path Path, ok bool) {
+	if opts == nil {
+		opts = &Options[T]{}
+	}
+	if _, walkable := tileCost(c, opts, start[0], start[1]); !walkable {
+		return path, false
+	}
+	if _, walkable := tileCost(c, opts, goal[0], goal[1]); !walkable {
+		return path, false
+	}
+
+	nodes := map[[2]int]*node{start: {pos: start, index: -1}}
+	open := &openList{}
+	heap.Push(open, nodes[start])
+	expanded := 0
+
+	for open.Len() > 0 {
+		current := heap.Pop(open).(*node)
+		current.closed = true
+		if current.pos == goal {
+			return buildPath(c, current, opts.AgentSize), true
+		}
+		if expanded++; opts.MaxNodes > 0 && expanded > opts.MaxNodes {
+			return path, false
+		}
+
+		forEachNeighbor(c, opts, current.pos, func(next [2]int, stepCost float64) {
+			g := current.g + stepCost
+			n, seen := nodes[next]
+			if seen && (n.closed || g >= n.g) {
+				return
+			}
+			if !seen {
+				n = &node{pos: next, index: -1}
+				nodes[next] = n
+			}
+			n.g = g
+			n.f = g + heuristic(next, goal, opts.Diagonal)
+			n.parent = current
+			if n.index < 0 {
+				heap.Push(open, n)
+			} else {
+				heap.Fix(open, n.index)
+			}
+		})
+	}
+	return path, false
+}
+
+// TileToWorld returns the center of the tile in world pixels
+func TileToWorld[T tilecollider.Integer](c *tilecollider.Collider[T], tile [2]int) [2]float64 {
+	return [2]float64{
+		(float64(tile[0]) + 0.5) * float64(c.TileSize[0]),
+		(float64(tile[1]) + 0.5) * float64(c.TileSize[1]),
+	}
+}
+
+// AgentToWorld returns the center of an agent of size by size tiles whose top-left tile is tile, in world pixels.
+// For sizes of 0 and 1 it is the center of the tile.
+func AgentToWorld[T tilecollider.Integer](c *tilecollider.Collider[T], tile [2]int, size int) [2]float64 {
+	half := float64(max(size, 1)) / 2
+	return [2]float64{
+		(float64(tile[0]) + half) * float64(c.TileSize[0]),
+		(float64(tile[1]) + half) * float64(c.TileSize[1]),
+	}
+}
+
+// WorldToAgent returns the top-left tile of an agent of size by size tiles centered at the world position.
+// It is the inverse of AgentToWorld.
+func WorldToAgent[T tilecollider.Integer](c *tilecollider.Collider[T], x, y float64, size int) [2]int {
+	offset := float64(max(size, 1)-1) / 2
+	return [2]int{
+		int(math.Floor(x/float64(c.TileSize[0]) - offset)),
+		int(math.Floor(y/float64(c.TileSize[1]) - offset)),
+	}
+}
+
+// WorldToTile returns the tile containing the world position
+func WorldToTile[T tilecollider.Integer](c *tilecollider.Collider[T], x, y float64) [2]int {
+	return [2]int{
+		int(math.Floor(x / float64(c.TileSize[0]))),
+		int(math.Floor(y / float64(c.TileSize[1]))),
+	}
+}
+
+// forEachNeighbor calls fn for every walkable neighbor of the tile with the cost of moving there.
+// Diagonal moves are only allowed if both adjacent orthogonal tiles are walkable.
+func forEachNeighbor[T tilecollider.Integer](c *tilecollider.Collider[T], opts *Options[T], pos [2]int, fn func(next [2]int, cost float64)) {
+	var open [4]bool
+	for i, d := range orthogonal {
+		next := [2]int{pos[0] + d[0], pos[1] + d[1]}
+		if cost, ok := tileCost(c, opts, next[0], next[1]); ok {
+			open[i] = true
+			fn(next, cost)
+		}
+	}
+	if !opts.Diagonal {
+		return
+	}
+	for i, d := range diagonal {
+		// orthogonal is ordered so that the neighbors of diagonal i are i and (i+1)%4
+		if !open[i] || !open[(i+1)%4] {
+			continue
+		}
+		next := [2]int{pos[0] + d[0], pos[1] + d[1]}
+		if cost, ok := tileCost(c, opts, next[0], next[1]); ok {
+			fn(next, cost*math.Sqrt2)
+		}
+	}
+}
+
+var (
+	orthogonal = [4][2]int{{1, 0}, {0, 1}, {-1, 0}, {0, -1}}
+	diagonal   = [4][2]int{{1, 1}, {-1, 1}, {-1, -1}, {1, -1}}
+)
+
+// tileCost returns the cost of entering the tile and whether it is walkable
+func tileCost[T tilecollider.Integer](c *tilecollider.Collider[T], opts *Options[T], x, y int) (float64, bool) {
+	if r := opts.Region; r[2] > 0 && (x < r[0] || y < r[1] || x >= r[0]+r[2] || y >= r[1]+r[3]) {
+		return 0, false
+	}
+	id, ok := c.Tile(x, y)
+	if !ok || c.IsSolid(id) {
+		return 0, false
+	}
+	if opts.AgentSize > 1 && !fits(c, opts, x, y) {
+		return 0, false
+	}
+	if opts.Cost == nil {
+		return 1, true
+	}
+	cost := opts.Cost(id, x, y)
+	return cost, !math.IsInf(cost, 1)
+}
+
+// heuristic returns the Manhattan or octile distance between two tiles
+func heuristic(a, b [2]int, diagonal bool) float64 {
+	dx, dy := math.Abs(float64(a[0]-b[0])), math.Abs(float64(a[1]-b[1]))
+	if !diagonal {
+		return dx + dy
+	}
+	return max(dx, dy) + (math.Sqrt2-1)*min(dx, dy)
+}
+
+func buildPath[T tilecollider.Integer](c *tilecollider.Collider[T], goal *node, agentSize int) Path {
+	path := Path{Cost: goal.g}
+	for n := goal; n != nil; n = n.parent {
+		path.Tiles = append(path.Tiles, n.pos)
+	}
+	for i, j := 0, len(path.Tiles)-1; i < j; i, j = i+1, j-1 {
+		path.Tiles[i], path.Tiles[j] = path.Tiles[j], path.Tiles[i]
+	}
+	path.World = make([][2]float64, len(path.Tiles))
+	for i, t := range path.Tiles {
+		path.World[i] = AgentToWorld(c, t, agentSize)
+	}
+	return path
+}
+
+// boundedBounds returns the bounds of the collider's tiles, or ErrUnbounded if the tile source is unbounded
+func boundedBounds[T tilecollider.Integer](c *tilecollider.Collider[T], what string) (minX, minY, maxX, maxY int, err error) {
+	minX, minY, maxX, maxY = c.Bounds()
+	if minX == math.MinInt || minY == math.MinInt || maxX == math.MaxInt || maxY == math.MaxInt {
+		return 0, 0, 0, 0, fmt.Errorf("%w: %s needs a bounded map", ErrUnbounded, what)
+	}
+	return minX, minY, maxX, maxY, nil
+}
+
+type node struct {
+	pos    [2]int
+	g, f   float64
+	parent *node
+	index  int
+	closed bool
+}
+
+// openList is a min-heap of nodes ordered by f
+type openList []*node
+
+func (o openList) Len() int { return len(o) }
+
+func (o openList) Less(i, j int) bool {
+	if o[i].f == o[j].f {
+		return o[i].g > o[j].g
+	}
+	return o[i].f < o[j].f
+}
+
+func (o openList) Swap(i, j int) {
+	o[i], o[j] = o[j], o[i]
+	o[i].index = i
+	o[j].index = j
+}
+
+func (o *openList) Push(x any) {
+	n := x.(*node)
+	n.index = len(*o)
+	*o = append(*o, n)
+}
+
+func (o *openList) Pop() any {
+	old := *o
+	n := old[len(old)-1]
+	old[len(old)-1] = nil
+	n.index = -1
+	*o = old[:len(old)-1]
+	return n
+}
@@ -0,0 +1,214 @@
+package pathfind
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+const level = `
+	..........
+	.######...
+	.#....#...
+	.#.##.#...
+	...#......
+	####.#####
+	..........`
+
+func newLevel(t *testing.T) *tilecollider.Collider[uint8] {
+	t.Helper()
+	c, err := tilecollider.NewColliderASCII[uint8](level, nil, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// must panics if err is not nil and returns v, e.g. must(NewFlowField(c, goals, nil))
+func must[V any](v V, err error) V {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// randCollider returns a w by h collider where about a third of the tiles are solid
+func randCollider(r *rand.Rand, w, h int) *tilecollider.Collider[uint8] {
+	m := make([][]uint8, h)
+	for y := range m {
+		m[y] = make([]uint8, w)
+		for x := range m[y] {
+			if r.Intn(3) == 0 {
+				m[y][x] = 1
+			}
+		}
+	}
+	return tilecollider.NewCollider(m, 16, 16)
+}
+
+// dijkstra returns the cost of the cheapest path to every reachable tile by relaxing all tiles until nothing changes
+func dijkstra[T tilecollider.Integer](c *tilecollider.Collider[T], start [2]int, opts *Options[T]) map[[2]int]float64 {
+	dist := map[[2]int]float64{start: 0}
+	for changed := true; changed; {
+		changed = false
+		for pos, d := range dist {
+			forEachNeighbor(c, opts, pos, func(next [2]int, cost float64) {
+				if old, ok := dist[next]; !ok || d+cost < old-1e-9 {
+					dist[next] = d + cost
+					changed = true
+				}
+			})
+		}
+	}
+	return dist
+}
+
+// checkPath fails if the path is not a connected sequence of walkable tiles from start to goal with the given cost
+func checkPath[T tilecollider.Integer](t *testing.T, c *tilecollider.Collider[T], p Path, start, goal [2]int, opts *Options[T]) {
+	t.Helper()
+	if len(p.Tiles) == 0 || p.Tiles[0] != start || p.Tiles[len(p.Tiles)-1] != goal {
+		t.Fatalf("path %v does not go from %v to %v", p.Tiles, start, goal)
+	}
+	cost := 0.0
+	for i := 1; i < len(p.Tiles); i++ {
+		step := math.Inf(1)
+		forEachNeighbor(c, opts, p.Tiles[i-1], func(next [2]int, c float64) {
+			if next == p.Tiles[i] {
+				step = c
+			}
+		})
+		if math.IsInf(step, 1) {
+			t.Fatalf("invalid step %v -> %v in %v", p.Tiles[i-1], p.Tiles[i], p.Tiles)
+		}
+		cost += step
+	}
+	if math.Abs(cost-p.Cost) > 1e-9 {
+		t.Fatalf("path cost %v, steps sum to %v", p.Cost, cost)
+	}
+	if len(p.World) != len(p.Tiles) {
+		t.Fatalf("%d world points for %d tiles", len(p.World), len(p.Tiles))
+	}
+}
+
+func TestFindPath(t *testing.T) {
+	c := newLevel(t)
+	start, goal := [2]int{2, 2}, [2]int{0, 6}
+	tests := []struct {
+		name string
+		opts *Options[uint8]
+	}{
+		{"orthogonal", nil},
+		{"diagonal", &Options[uint8]{Diagonal: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, ok := FindPath(c, start, goal, tt.opts)
+			if !ok {
+				t.Fatal("no path")
+			}
+			opts := tt.opts
+			if opts == nil {
+				opts = &Options[uint8]{}
+			}
+			checkPath(t, c, p, start, goal, opts)
+			if want := dijkstra(c, start, opts)[goal]; math.Abs(p.Cost-want) > 1e-9 {
+				t.Errorf("cost = %v, want %v", p.Cost, want)
+			}
+			for i, tile := range p.Tiles {
+				if p.World[i] != TileToWorld(c, tile) {
+					t.Errorf("World[%d] = %v, want center of %v", i, p.World[i], tile)
+				}
+			}
+		})
+	}
+}
+
+func TestFindPathMatchesDijkstra(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for i := range 300 {
+		c := randCollider(r, 4+r.Intn(12), 4+r.Intn(12))
+		opts := &Options[uint8]{Diagonal: r.Intn(2) == 0}
+		if r.Intn(2) == 0 {
+			opts.Cost = func(id uint8, x, y int) float64 { return float64(1 + (x*7+y*3)%4) }
+		}
+		w, h := len(c.TileMap[0]), len(c.TileMap)
+		start := [2]int{r.Intn(w), r.Intn(h)}
+		c.TileMap[start[1]][start[0]] = 0
+		dist := dijkstra(c, start, opts)
+		for range 5 {
+			goal := [2]int{r.Intn(w), r.Intn(h)}
+			p, ok := FindPath(c, start, goal, opts)
+			want, reachable := dist[goal]
+			if ok != reachable {
+				t.Fatalf("case %d: %v -> %v: ok = %v, want %v", i, start, goal, ok, reachable)
+			}
+			if !ok {
+				continue
+			}
+			checkPath(t, c, p, start, goal, opts)
+			if math.Abs(p.Cost-want) > 1e-9 {
+				t.Fatalf("case %d: %v -> %v: cost %v, want %v", i, start, goal, p.Cost, want)
+			}
+		}
+	}
+}
+
+func TestFindPathNoCornerCutting(t *testing.T) {
+	c, err := tilecollider.NewColliderASCII[uint8](`
+		.#
+		..`, nil, 16, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, ok := FindPath(c, [2]int{0, 0}, [2]int{1, 1}, &Options[uint8]{Diagonal: true})
+	if !ok || len(p.Tiles) != 3 || p.Cost != 2 {
+		t.Errorf("path %v cost %v, want the orthogonal detour", p.Tiles, p.Cost)
+	}
+}
+
+func TestFindPathLimits(t *testing.T) {
+	c := newLevel(t)
+	block := func(id uint8, x, y int) float64 {
+		if x == 4 && y == 5 {
+			return math.Inf(1)
+		}
+		return 1
+	}
+	tests := []struct {
+		name        string
+		start, goal [2]int
+		opts        *Options[uint8]
+		ok          bool
+	}{
+		{"blocked by cost", [2]int{0, 0}, [2]int{9, 6}, &Options[uint8]{Diagonal: true, Cost: block}, false},
+		{"solid start", [2]int{1, 1}, [2]int{0, 0}, nil, false},
+		{"solid goal", [2]int{0, 0}, [2]int{1, 1}, nil, false},
+		{"outside map", [2]int{0, 0}, [2]int{10, 0}, nil, false},
+		{"max nodes", [2]int{0, 0}, [2]int{9, 6}, &Options[uint8]{MaxNodes: 10}, false},
+		{"enough nodes", [2]int{0, 0}, [2]int{9, 6}, &Options[uint8]{MaxNodes: 100}, true},
+		{"outside region", [2]int{0, 0}, [2]int{9, 6}, &Options[uint8]{Region: [4]int{0, 0, 10, 5}}, false},
+		{"inside region", [2]int{0, 0}, [2]int{9, 4}, &Options[uint8]{Region: [4]int{0, 0, 10, 5}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := FindPath(c, tt.start, tt.goal, tt.opts); ok != tt.ok {
+				t.Errorf("ok = %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestTileToWorld(t *testing.T) {
+	c := tilecollider.NewCollider[uint8](nil, 16, 8)
+	if got, want := TileToWorld(c, [2]int{2, -1}), [2]float64{40, -4}; got != want {
+		t.Errorf("TileToWorld = %v, want %v", got, want)
+	}
+	if got, want := WorldToTile(c, 40, -4), [2]int{2, -1}; got != want {
+		t.Errorf("WorldToTile = %v, want %v", got, want)
+	}
+	if got, want := WorldToTile(c, -0.5, 7.9), [2]int{-1, 0}; got != want {
+		t.Errorf("WorldToTile = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,385 @@
+// Package replay records the Collide calls of a tilecollider.Collider and replays them to detect divergences
+// between library versions.
+//
+// A recording stores the inputs and results of every call together with a hash of the collision state.
+// A binary snapshot of the collider is stored whenever the hash changes, so recordings can be replayed without the game.
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"maps"
+	"math"
+	"reflect"
+	"unsafe"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// Version is the version of the recording format
+const Version = 1
+
+var magic = [4]byte{'T', 'C', 'R', 'P'}
+
+var (
+	ErrFormat    = errors.New("replay: invalid recording")
+	ErrVersion   = errors.New("replay: unsupported recording version")
+	ErrType      = errors.New("replay: recording tile type mismatch")
+	ErrSolidFunc = errors.New("replay: colliders with SolidFunc cannot be recorded")
+)
+
+// Record types
+const (
+	recordSnapshot byte = 1
+	recordCall     byte = 2
+)
+
+// Call stores the inputs and results of a Collide call
+type Call[T tilecollider.Integer] struct {
+	Hash       uint64                          // FNV-1a hash of the collider snapshot
+	Rect       [4]float64                      // X, Y, width and height
+	Move       [2]float64                      // Attempted movement
+	Result     [2]float64                      // Allowed movement
+	Collisions []tilecollider.CollisionInfo[T] // Collisions of the call
+}
+
+// Recorder records the Collide calls of a collider to a writer.
+//
+// The collider is snapshotted and hashed when its state changes. Tile changes are detected with a watcher, so
+// they must be made with SetTile, FillRect or ReadSnapshot; call Invalidate after writing tiles in another way.
+// Reassigning TileMap, Source, TileSize, NonSolidTileID, StaticCheck or Fluids is detected on every call.
+// Colliders that use SolidFunc cannot be recorded because functions are not part of the snapshot.
+type Recorder[T tilecollider.Integer] struct {
+	Collider *tilecollider.Collider[T]
+
+	w        *bufio.Writer
+	watcher  *tilecollider.TileWatcher[T]
+	state    state[T]
+	fluids   map[T]tilecollider.Fluid
+	dirty    bool
+	lastHash uint64
+	calls    int
+	err      error
+}
+
+// state holds the collider fields that are compared on every call to detect reassignments
+type state[T tilecollider.Integer] struct {
+	tileSize    [2]int
+	nonSolid    T
+	staticCheck bool
+	tileMap     *[]T
+	rows        int
+	source      tilecollider.TileSource[T]
+}
+
+// NewRecorder creates a new recorder for the collider and writes the recording header.
+// It returns ErrSolidFunc if the collider uses SolidFunc.
+func NewRecorder[T tilecollider.Integer](w io.Writer, c *tilecollider.Collider[T]) (*Recorder[T], error) {
+	if c.SolidFunc != nil {
+		return nil, ErrSolidFunc
+	}
+	r := &Recorder[T]{Collider: c, w: bufio.NewWriter(w), watcher: c.Watch(nil), dirty: true}
+	bits, signed := intKind[T]()
+	r.w.Write(magic[:])
+	r.w.WriteByte(Version)
+	r.w.WriteByte(byte(bits))
+	r.w.WriteByte(boolByte(signed))
+	return r, r.w.Flush()
+}
+
+// Collide calls Collider.Collide and records its inputs and results.
+// Recording errors do not affect the call; they are returned by Err and Flush.
+func (r *Recorder[T]) Collide(rectX, rectY, rectW, rectH, moveX, moveY float64, onCollide tilecollider.CollisionCallback[T]) (float64, float64) {
+	hash := r.snapshot()
+	dx, dy := r.Collider.Collide(rectX, rectY, rectW, rectH, moveX, moveY, onCollide)
+	if r.err == nil {
+		r.err = writeCall(r.w, Call[T]{
+			Hash:       hash,
+			Rect:       [4]float64{rectX, rectY, rectW, rectH},
+			Move:       [2]float64{moveX, moveY},
+			Result:     [2]float64{dx, dy},
+			Collisions: r.Collider.Collisions,
+		})
+		r.calls++
+	}
+	return dx, dy
+}
+
+// Invalidate makes the next Collide call snapshot the collider. Use it after changing tiles without SetTile or FillRect.
+func (r *Recorder[T]) Invalidate() {
+	r.dirty = true
+}
+
+// Calls returns the number of recorded calls
+func (r *Recorder[T]) Calls() int {
+	return r.calls
+}
+
+// Err returns the first recording error
+func (r *Recorder[T]) Err() error {
+	return r.err
+}
+
+// Flush writes buffered records to the underlying writer
+func (r *Recorder[T]) Flush() error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.w.Flush()
+}
+
+// Close flushes the recording and stops watching the collider
+func (r *Recorder[T]) Close() error {
+	r.Collider.Unwatch(r.watcher)
+	return r.Flush()
+}
+
+// snapshot returns the hash of the collision state and records a snapshot if it changed
+func (r *Recorder[T]) snapshot() uint64 {
+	if r.err != nil {
+		return 0
+	}
+	c := r.Collider
+	if c.SolidFunc != nil {
+		r.err = ErrSolidFunc
+		return 0
+	}
+	st := state[T]{
+		tileSize:    c.TileSize,
+		nonSolid:    c.NonSolidTileID,
+		staticCheck: c.StaticCheck,
+		tileMap:     unsafe.SliceData(c.TileMap),
+		rows:        len(c.TileMap),
+		source:      c.Source,
+	}
+	if len(r.watcher.Drain()) > 0 || st.tileSize != r.state.tileSize || st.nonSolid != r.state.nonSolid ||
+		st.staticCheck != r.state.staticCheck || st.tileMap != r.state.tileMap || st.rows != r.state.rows ||
+		!sameSource(st.source, r.state.source) || !maps.Equal(c.Fluids, r.fluids) {
+		r.dirty = true
+	}
+	if !r.dirty {
+		return r.lastHash
+	}
+
+	data, err := c.MarshalBinary()
+	if err != nil {
+		r.err = err
+		return 0
+	}
+	r.state, r.fluids, r.dirty = st, maps.Clone(c.Fluids), false
+	hash := Hash(data)
+	if r.calls == 0 || hash != r.lastHash {
+		r.w.WriteByte(recordSnapshot)
+		r.w.Write(binary.AppendUvarint(nil, uint64(len(data))))
+		_, r.err = r.w.Write(data)
+		r.lastHash = hash
+	}
+	return hash
+}
+
+// sameSource reports whether a and b are the same tile storage.
+// Sources that cannot be compared are reported as different.
+func sameSource[T tilecollider.Integer](a, b tilecollider.TileSource[T]) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch {
+	case va.Type() != vb.Type():
+		return false
+	case va.Comparable():
+		return va.Equal(vb)
+	case va.Kind() == reflect.Slice:
+		return va.Pointer() == vb.Pointer() && va.Len() == vb.Len()
+	}
+	return false
+}
+
+// Hash returns the FNV-1a hash of a collider snapshot
+func Hash(snapshot []byte) uint64 {
+	h := fnv.New64a()
+	h.Write(snapshot)
+	return h.Sum64()
+}
+
+// Divergence describes a replayed call whose results differ from the recording
+type Divergence[T tilecollider.Integer] struct {
+	Index int     // Index of the call in the recording
+	Want  Call[T] // Recorded call
+	Got   Call[T] // Replayed call
+}
+
+func (d Divergence[T]) String() string {
+	return fmt.Sprintf("call %d: Collide(%v, %v) = %v %v, recorded %v %v",
+		d.Index, d.Want.Rect, d.Want.Move, d.Got.Result, d.Got.Collisions, d.Want.Result, d.Want.Collisions)
+}
+
+// Replay re-runs the recorded calls on a collider restored from the recorded snapshots and returns the calls
+// whose results or collisions differ from the recording. It also returns the number of replayed calls.
+func Replay[T tilecollider.Integer](r io.Reader) (divergences []Divergence[T], calls int, err error) {
+	br := bufio.NewReader(r)
+	if err := readHeader[T](br); err != nil {
+		return nil, 0, err
+	}
+
+	c := tilecollider.NewCollider[T](nil, 1, 1)
+	var hash uint64
+	for {
+		kind, err := br.ReadByte()
+		if err == io.EOF {
+			return divergences, calls, nil
+		}
+		if err != nil {
+			return divergences, calls, err
+		}
+
+		switch kind {
+		case recordSnapshot:
+			n, err := binary.ReadUvarint(br)
+			if err != nil || n > math.MaxInt32 {
+				return divergences, calls, ErrFormat
+			}
+			data := make([]byte, n)
+			if _, err := io.ReadFull(br, data); err != nil {
+				return divergences, calls, ErrFormat
+			}
+			if err := c.UnmarshalBinary(data); err != nil {
+				return divergences, calls, err
+			}
+			hash = Hash(data)
+		case recordCall:
+			want, err := readCall[T](br)
+			if err != nil {
+				return divergences, calls, err
+			}
+			if want.Hash != hash {
+				return divergences, calls, fmt.Errorf("%w: call %d has no matching snapshot", ErrFormat, calls)
+			}
+			got := Call[T]{Hash: hash, Rect: want.Rect, Move: want.Move}
+			got.Result[0], got.Result[1] = c.Collide(want.Rect[0], want.Rect[1], want.Rect[2], want.Rect[3], want.Move[0], want.Move[1], nil)
+			got.Collisions = append([]tilecollider.CollisionInfo[T](nil), c.Collisions...)
+			if !sameResult(want, got) {
+				divergences = append(divergences, Divergence[T]{Index: calls, Want: want, Got: got})
+			}
+			calls++
+		default:
+			return divergences, calls, fmt.Errorf("%w: unknown record type %d", ErrFormat, kind)
+		}
+	}
+}
+
+// ReadKind reads the recording header and returns the size in bits and the signedness of its tile type
+func ReadKind(r io.Reader) (bits int, signed bool, err error) {
+	var header [7]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return 0, false, ErrFormat
+	}
+	if [4]byte(header[:4]) != magic {
+		return 0, false, ErrFormat
+	}
+	if header[4] != Version {
+		return 0, false, fmt.Errorf("%w: %d", ErrVersion, header[4])
+	}
+	return int(header[5]), header[6] != 0, nil
+}
+
+// intKind returns the size in bits and the signedness of T.
+// It matches the tile type fields of tilecollider snapshots.
+func intKind[T tilecollider.Integer]() (bits int, signed bool) {
+	var zero T
+	return int(unsafe.Sizeof(zero)) * 8, ^zero < 0
+}
+
+func readHeader[T tilecollider.Integer](r io.Reader) error {
+	bits, signed, err := ReadKind(r)
+	if err != nil {
+		return err
+	}
+	if wantBits, wantSigned := intKind[T](); bits != wantBits || signed != wantSigned {
+		return fmt.Errorf("%w: recording has %d-bit signed=%v tiles", ErrType, bits, signed)
+	}
+	return nil
+}
+
+func sameResult[T tilecollider.Integer](a, b Call[T]) bool {
+	if math.Float64bits(a.Result[0]) != math.Float64bits(b.Result[0]) || math.Float64bits(a.Result[1]) != math.Float64bits(b.Result[1]) {
+		return false
+	}
+	if len(a.Collisions) != len(b.Collisions) {
+		return false
+	}
+	for i := range a.Collisions {
+		if a.Collisions[i] != b.Collisions[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func writeCall[T tilecollider.Integer](w *bufio.Writer, call Call[T]) error {
+	buf := []byte{recordCall}
+	buf = binary.LittleEndian.AppendUint64(buf, call.Hash)
+	for _, v := range [...]float64{call.Rect[0], call.Rect[1], call.Rect[2], call.Rect[3], call.Move[0], call.Move[1], call.Result[0], call.Result[1]} {
+		buf = binary.LittleEndian.AppendUint64(buf, math.Float64bits(v))
+	}
+	buf = binary.AppendUvarint(buf, uint64(len(call.Collisions)))
+	for _, c := range call.Collisions {
+		buf = binary.AppendUvarint(buf, uint64(c.TileID))
+		buf = binary.AppendVarint(buf, int64(c.TileCoords[0]))
+		buf = binary.AppendVarint(buf, int64(c.TileCoords[1]))
+		buf = binary.AppendVarint(buf, int64(c.Normal[0]))
+		buf = binary.AppendVarint(buf, int64(c.Normal[1]))
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+func readCall[T tilecollider.Integer](r *bufio.Reader) (Call[T], error) {
+	var call Call[T]
+	var fixed [8 + 8*8]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return call, ErrFormat
+	}
+	call.Hash = binary.LittleEndian.Uint64(fixed[:])
+	var values [8]float64
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(fixed[8+8*i:]))
+	}
+	call.Rect = [4]float64(values[:4])
+	call.Move = [2]float64(values[4:6])
+	call.Result = [2]float64(values[6:8])
+
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > math.MaxInt32 {
+		return call, ErrFormat
+	}
+	for range n {
+		id, err := binary.ReadUvarint(r)
+		if err != nil {
+			return call, ErrFormat
+		}
+		var v [4]int64
+		for i := range v {
+			if v[i], err = binary.ReadVarint(r); err != nil {
+				return call, ErrFormat
+			}
+		}
+		call.Collisions = append(call.Collisions, tilecollider.CollisionInfo[T]{
+			TileID:     T(id),
+			TileCoords: [2]int{int(v[0]), int(v[1])},
+			Normal:     [2]int{int(v[2]), int(v[3])},
+		})
+	}
+	return call, nil
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
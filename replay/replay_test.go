@@ -0,0 +1,245 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math/rand"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+func newTestCollider() *tilecollider.Collider[uint8] {
+	r := rand.New(rand.NewSource(1))
+	m := make([][]uint8, 20)
+	for y := range m {
+		m[y] = make([]uint8, 30)
+		for x := range m[y] {
+			if r.Intn(4) == 0 {
+				m[y][x] = uint8(1 + r.Intn(3))
+			}
+		}
+	}
+	return tilecollider.NewCollider(m, 16, 16)
+}
+
+// snapshots returns the number of snapshot records of a recording
+func snapshots(t *testing.T, recording []byte) int {
+	t.Helper()
+	br := bufio.NewReader(bytes.NewReader(recording))
+	if err := readHeader[uint8](br); err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for {
+		kind, err := br.ReadByte()
+		if err != nil {
+			return n
+		}
+		switch kind {
+		case recordSnapshot:
+			size, _ := binary.ReadUvarint(br)
+			br.Discard(int(size))
+			n++
+		case recordCall:
+			if _, err := readCall[uint8](br); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+}
+
+// collideRandom makes n random Collide calls
+func collideRandom(rec *Recorder[uint8], r *rand.Rand, n int) {
+	for range n {
+		rec.Collide(r.Float64()*480, r.Float64()*320, 1+r.Float64()*30, 1+r.Float64()*30, r.Float64()*60-30, r.Float64()*60-30, nil)
+	}
+}
+
+func TestRecordReplay(t *testing.T) {
+	c := newTestCollider()
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(2))
+
+	collideRandom(rec, r, 100)
+	c.SetTile(3, 3, 1)
+	collideRandom(rec, r, 100)
+	// Setting a tile back restores the first state; it is stored again because the hash changed
+	c.SetTile(3, 3, c.TileMap[3][3])
+	c.FillRect(0, 0, 5, 5, 0)
+	collideRandom(rec, r, 100)
+	c.StaticCheck = true
+	collideRandom(rec, r, 100)
+	c.Fluids = map[uint8]tilecollider.Fluid{2: {Drag: 0.5}}
+	collideRandom(rec, r, 100)
+	c.Fluids[3] = tilecollider.Fluid{Drag: 0.1}
+	collideRandom(rec, r, 100)
+	c.TileMap[0][0] = 1
+	rec.Invalidate()
+	collideRandom(rec, r, 100)
+	if err := rec.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Calls() != 700 {
+		t.Errorf("Calls() = %d, want 700", rec.Calls())
+	}
+	if got := snapshots(t, buf.Bytes()); got != 7 {
+		t.Errorf("%d snapshots recorded, want 7", got)
+	}
+
+	divergences, calls, err := Replay[uint8](bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 700 || len(divergences) != 0 {
+		t.Errorf("replayed %d calls with divergences %v", calls, divergences)
+	}
+}
+
+func TestRecorderSnapshotsOnlyOnChange(t *testing.T) {
+	c := newTestCollider()
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(3))
+	collideRandom(rec, r, 500)
+	// Setting a tile to its current ID, reassigning equal values and invalidating an unchanged state
+	// do not store another snapshot
+	c.SetTile(0, 0, c.TileMap[0][0])
+	c.TileSize = [2]int{16, 16}
+	c.Source = nil
+	rec.Invalidate()
+	collideRandom(rec, r, 500)
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := snapshots(t, buf.Bytes()); got != 1 {
+		t.Errorf("%d snapshots recorded, want 1", got)
+	}
+}
+
+func TestRecorderSource(t *testing.T) {
+	c := newTestCollider()
+	grid := tilecollider.GridFromSlice(c.TileMap)
+	c.Source = grid
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewSource(4))
+	collideRandom(rec, r, 50)
+	c.Source = tilecollider.SliceSource[uint8](grid.Slice())
+	collideRandom(rec, r, 50)
+	// A new SliceSource over the same rows is the same storage
+	c.Source = tilecollider.SliceSource[uint8](c.Source.(tilecollider.SliceSource[uint8]))
+	collideRandom(rec, r, 50)
+	c.SetTile(1, 1, 9)
+	collideRandom(rec, r, 50)
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// The SliceSource has the same tiles, so only the tile change stores another snapshot
+	if got := snapshots(t, buf.Bytes()); got != 2 {
+		t.Errorf("%d snapshots recorded, want 2", got)
+	}
+	if divergences, _, err := Replay[uint8](bytes.NewReader(buf.Bytes())); err != nil || len(divergences) != 0 {
+		t.Errorf("Replay: %v %v", divergences, err)
+	}
+}
+
+func TestRecorderSolidFunc(t *testing.T) {
+	c := newTestCollider()
+	c.SolidFunc = func(id uint8) bool { return id == 1 }
+	if _, err := NewRecorder(&bytes.Buffer{}, c); !errors.Is(err, ErrSolidFunc) {
+		t.Errorf("NewRecorder: err = %v, want %v", err, ErrSolidFunc)
+	}
+
+	c.SolidFunc = nil
+	rec, err := NewRecorder(&bytes.Buffer{}, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SolidFunc = func(id uint8) bool { return id == 1 }
+	rec.Collide(0, 0, 10, 10, 5, 5, nil)
+	if err := rec.Err(); !errors.Is(err, ErrSolidFunc) {
+		t.Errorf("Err() = %v, want %v", err, ErrSolidFunc)
+	}
+}
+
+func TestReplayDivergence(t *testing.T) {
+	c := newTestCollider()
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Collide(40, 40, 10, 10, 100, 0, nil)
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	// Change the recorded X result. The call follows the header and the snapshot record
+	// and starts with its type, hash, rect and move.
+	snapshot, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	call := 7 + 1 + len(binary.AppendUvarint(nil, uint64(len(snapshot)))) + len(snapshot)
+	if data[call] != recordCall {
+		t.Fatalf("no call record at %d", call)
+	}
+	binary.LittleEndian.PutUint64(data[call+1+8+6*8:], 0)
+
+	divergences, calls, err := Replay[uint8](bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 || len(divergences) != 1 || divergences[0].Index != 0 {
+		t.Fatalf("calls %d, divergences %v", calls, divergences)
+	}
+	if d := divergences[0]; d.Want.Result[0] != 0 || d.Got.Result[0] == 0 {
+		t.Errorf("divergence %v", d)
+	}
+}
+
+func TestReplayErrors(t *testing.T) {
+	c := newTestCollider()
+	var buf bytes.Buffer
+	rec, err := NewRecorder(&buf, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec.Collide(40, 40, 10, 10, 5, 5, nil)
+	if err := rec.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	if _, _, err := Replay[int16](bytes.NewReader(data)); !errors.Is(err, ErrType) {
+		t.Errorf("wrong type: err = %v, want %v", err, ErrType)
+	}
+	badVersion := bytes.Clone(data)
+	badVersion[4] = Version + 1
+	if _, _, err := Replay[uint8](bytes.NewReader(badVersion)); !errors.Is(err, ErrVersion) {
+		t.Errorf("bad version: err = %v, want %v", err, ErrVersion)
+	}
+	if _, _, err := Replay[uint8](bytes.NewReader(data[:3])); !errors.Is(err, ErrFormat) {
+		t.Errorf("short header: err = %v, want %v", err, ErrFormat)
+	}
+	if _, _, err := Replay[uint8](bytes.NewReader(data[:len(data)-1])); !errors.Is(err, ErrFormat) {
+		t.Errorf("truncated call: err = %v, want %v", err, ErrFormat)
+	}
+	bits, signed, err := ReadKind(bytes.NewReader(data))
+	if err != nil || bits != 8 || signed {
+		t.Errorf("ReadKind() = %d, %v, %v", bits, signed, err)
+	}
+}
@@ -0,0 +1,174 @@
+// Package rollback provides rollback netcode support for tilecollider simulations.
+//
+// A Session keeps a ring buffer of per-tick state snapshots. Missing remote inputs are predicted by repeating
+// the last known input of the player. When a late input differs from the prediction, the session rewinds to
+// the affected tick and resimulates forward.
+package rollback
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrTooOld    = errors.New("rollback: input is older than the rollback window")
+	ErrTooFar    = errors.New("rollback: input is too far in the future")
+	ErrBadPlayer = errors.New("rollback: invalid player index")
+	ErrConflict  = errors.New("rollback: input for this tick is already known")
+)
+
+// StepFunc advances the state by one tick using the inputs of all players (e.g. by calling Collider.Collide for every body)
+type StepFunc[S any, I comparable] func(state *S, tick int, inputs []I)
+
+// Session is a rollback simulation of S states driven by I inputs
+type Session[S any, I comparable] struct {
+	Step  StepFunc[S, I] // Advances the state by one tick
+	Clone func(S) S      // Optional. Deep copies a state. Defaults to plain assignment.
+
+	players   int
+	window    int
+	tick      int
+	rewind    int
+	rollbacks int
+	state     S
+	frames    []frame[S, I]
+	inputs    map[int][]I
+	known     map[int][]bool
+}
+
+// frame is the saved state at the start of a tick and the inputs used to simulate it
+type frame[S any, I comparable] struct {
+	tick   int
+	state  S
+	inputs []I
+}
+
+// NewSession creates a new session for the number of players starting at tick 0.
+// window is the number of past ticks that can be rolled back.
+func NewSession[S any, I comparable](players, window int, initial S, step StepFunc[S, I]) *Session[S, I] {
+	s := &Session[S, I]{
+		Step:    step,
+		players: players,
+		window:  max(1, window),
+		rewind:  -1,
+		state:   initial,
+		inputs:  make(map[int][]I),
+		known:   make(map[int][]bool),
+	}
+	s.frames = make([]frame[S, I], s.window)
+	for i := range s.frames {
+		s.frames[i].tick = -1
+	}
+	return s
+}
+
+// Tick returns the next tick to be simulated
+func (s *Session[S, I]) Tick() int {
+	return s.tick
+}
+
+// State returns the current state. Pending rollbacks are applied first.
+func (s *Session[S, I]) State() *S {
+	s.resimulate()
+	return &s.state
+}
+
+// Rollbacks returns the number of rollbacks performed
+func (s *Session[S, I]) Rollbacks() int {
+	return s.rollbacks
+}
+
+// AddInput adds the input of a player for a tick. Inputs can arrive late and out of order.
+// If a past input differs from the predicted one, the session rolls back on the next Advance or State call.
+func (s *Session[S, I]) AddInput(player, tick int, input I) error {
+	if player < 0 || player >= s.players {
+		return fmt.Errorf("%w: %d", ErrBadPlayer, player)
+	}
+	if tick < 0 || tick <= s.tick-s.window {
+		return fmt.Errorf("%w: tick %d, current %d", ErrTooOld, tick, s.tick)
+	}
+	if tick >= s.tick+s.window {
+		return fmt.Errorf("%w: tick %d, current %d", ErrTooFar, tick, s.tick)
+	}
+
+	if s.known[tick] == nil {
+		s.inputs[tick] = make([]I, s.players)
+		s.known[tick] = make([]bool, s.players)
+	}
+	if s.known[tick][player] {
+		if s.inputs[tick][player] != input {
+			return fmt.Errorf("%w: player %d, tick %d", ErrConflict, player, tick)
+		}
+		return nil
+	}
+	s.inputs[tick][player] = input
+	s.known[tick][player] = true
+
+	// Simulated ticks from here until the next known input of the player may have used a wrong prediction
+	for t := tick; t < s.tick; t++ {
+		if t > tick && s.known[t] != nil && s.known[t][player] {
+			break
+		}
+		if s.frames[t%s.window].inputs[player] != input {
+			if s.rewind < 0 || t < s.rewind {
+				s.rewind = t
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// Advance simulates the current tick with known or predicted inputs and moves to the next tick
+func (s *Session[S, I]) Advance() {
+	s.resimulate()
+	s.simulate(s.tick)
+	s.tick++
+	delete(s.inputs, s.tick-s.window-1)
+	delete(s.known, s.tick-s.window-1)
+}
+
+// resimulate rewinds to the earliest mispredicted tick and simulates forward to the current tick
+func (s *Session[S, I]) resimulate() {
+	if s.rewind < 0 {
+		return
+	}
+	s.rollbacks++
+	s.state = s.clone(s.frames[s.rewind%s.window].state)
+	for t := s.rewind; t < s.tick; t++ {
+		s.simulate(t)
+	}
+	s.rewind = -1
+}
+
+// simulate saves the state of the tick and steps it
+func (s *Session[S, I]) simulate(tick int) {
+	f := &s.frames[tick%s.window]
+	f.tick = tick
+	f.state = s.clone(s.state)
+	if f.inputs == nil {
+		f.inputs = make([]I, s.players)
+	}
+	for p := range f.inputs {
+		f.inputs[p] = s.input(p, tick)
+	}
+	s.Step(&s.state, tick, f.inputs)
+}
+
+// input returns the known input of the player for the tick, or the last known input before it
+func (s *Session[S, I]) input(player, tick int) I {
+	for t := tick; t > tick-s.window-1; t-- {
+		if known := s.known[t]; known != nil && known[player] {
+			return s.inputs[t][player]
+		}
+	}
+	var zero I
+	return zero
+}
+
+func (s *Session[S, I]) clone(state S) S {
+	if s.Clone != nil {
+		return s.Clone(state)
+	}
+	return state
+}
@@ -0,0 +1,228 @@
+package rollback
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// Input bits
+const (
+	left uint8 = 1 << iota
+	right
+	jump
+)
+
+var collider = tilecollider.NewCollider([][]uint8{
+	{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+	{1, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+	{1, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+	{1, 0, 0, 1, 1, 0, 0, 0, 0, 1},
+	{1, 0, 0, 0, 0, 0, 0, 1, 0, 1},
+	{1, 0, 0, 0, 0, 0, 1, 1, 0, 1},
+	{1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+}, 32, 32)
+
+// step moves every body of a small platformer by its input
+func step(bodies *[]tilecollider.Body, tick int, inputs []uint8) {
+	for i, in := range inputs {
+		b := &(*bodies)[i]
+		b.Vel[0] = 0
+		if in&left != 0 {
+			b.Vel[0] = -3
+		}
+		if in&right != 0 {
+			b.Vel[0] = 3
+		}
+		b.Vel[1] = min(b.Vel[1]+0.5, 12)
+		dx, dy := collider.Collide(b.Rect[0], b.Rect[1], b.Rect[2], b.Rect[3], b.Vel[0], b.Vel[1], nil)
+		for _, c := range collider.Collisions {
+			if c.Normal[1] == -1 && in&jump != 0 {
+				dy = 0
+				b.Vel[1] = -9
+			} else if c.Normal[1] != 0 {
+				b.Vel[1] = 0
+			}
+		}
+		b.Rect[0] += dx
+		b.Rect[1] += dy
+	}
+}
+
+func newBodySession(window int) *Session[[]tilecollider.Body, uint8] {
+	initial := []tilecollider.Body{
+		{Rect: [4]float64{40, 40, 20, 28}},
+		{Rect: [4]float64{240, 40, 20, 28}},
+	}
+	s := NewSession(2, window, initial, step)
+	s.Clone = slices.Clone[[]tilecollider.Body]
+	return s
+}
+
+type packet struct {
+	player, tick int
+	input        uint8
+	arrival      int
+}
+
+// peer is one machine with its own session and a queue of packets in flight to it
+type peer struct {
+	session *Session[[]tilecollider.Body, uint8]
+	inbox   []packet
+}
+
+// deliver adds the packets that arrived until now to the session in random order
+func (p *peer) deliver(t *testing.T, now int, net *rand.Rand) {
+	t.Helper()
+	net.Shuffle(len(p.inbox), func(i, j int) { p.inbox[i], p.inbox[j] = p.inbox[j], p.inbox[i] })
+	p.inbox = slices.DeleteFunc(p.inbox, func(pk packet) bool {
+		if pk.arrival > now {
+			return false
+		}
+		if err := p.session.AddInput(pk.player, pk.tick, pk.input); err != nil {
+			t.Fatal(err)
+		}
+		return true
+	})
+}
+
+func TestPeersInSync(t *testing.T) {
+	const ticks, window = 600, 16
+	tests := []struct {
+		seed       int64
+		maxLatency int // ticks
+	}{
+		{1, 1},
+		{2, 3},
+		{42, 10},
+		{7, window - 1},
+		{99, window - 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("seed %d latency %d", tt.seed, tt.maxLatency), func(t *testing.T) {
+			net := rand.New(rand.NewSource(tt.seed))
+			peers := []*peer{{session: newBodySession(window)}, {session: newBodySession(window)}}
+			// Reference simulation with every input known in time
+			ref := newBodySession(window)
+
+			for tick := range ticks {
+				for i, p := range peers {
+					input := uint8(net.Intn(8))
+					if err := p.session.AddInput(i, tick, input); err != nil {
+						t.Fatal(err)
+					}
+					if err := ref.AddInput(i, tick, input); err != nil {
+						t.Fatal(err)
+					}
+					// Send with random latency. Packets can overtake each other.
+					other := peers[1-i]
+					other.inbox = append(other.inbox, packet{i, tick, input, tick + 1 + net.Intn(tt.maxLatency)})
+				}
+				ref.Advance()
+				for _, p := range peers {
+					p.deliver(t, tick, net)
+					p.session.Advance()
+				}
+			}
+			for _, p := range peers {
+				p.deliver(t, ticks+tt.maxLatency, net)
+			}
+
+			want := *ref.State()
+			if ref.Rollbacks() != 0 {
+				t.Errorf("reference session rolled back %d times", ref.Rollbacks())
+			}
+			for i, p := range peers {
+				if got := *p.session.State(); !slices.Equal(got, want) {
+					t.Errorf("peer %d: state %v, want %v", i, got, want)
+				}
+				if tt.maxLatency > 1 && p.session.Rollbacks() == 0 {
+					t.Errorf("peer %d: no rollbacks", i)
+				}
+			}
+		})
+	}
+}
+
+// counter sums the inputs of all players
+func counter(state *int, tick int, inputs []int) {
+	for _, in := range inputs {
+		*state += in
+	}
+}
+
+func TestAddInputErrors(t *testing.T) {
+	s := NewSession(2, 4, 0, counter)
+	for range 10 {
+		s.Advance()
+	}
+	if err := s.AddInput(0, 8, 5); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		player, tick int
+		input        int
+		want         error
+	}{
+		{"too old", 0, 6, 1, ErrTooOld},
+		{"oldest in window", 0, 7, 1, nil},
+		{"too far", 0, 14, 1, ErrTooFar},
+		{"farthest in window", 0, 13, 1, nil},
+		{"conflict", 0, 8, 6, ErrConflict},
+		{"repeated", 0, 8, 5, nil},
+		{"negative player", -1, 10, 1, ErrBadPlayer},
+		{"player out of range", 2, 10, 1, ErrBadPlayer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.AddInput(tt.player, tt.tick, tt.input)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("AddInput(%d, %d, %d) = %v, want %v", tt.player, tt.tick, tt.input, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddInputNegativeTick(t *testing.T) {
+	// Early in the session negative ticks are inside the window but never exist
+	s := NewSession(2, 8, 0, counter)
+	for range 3 {
+		s.Advance()
+	}
+	if err := s.AddInput(0, -1, 5); !errors.Is(err, ErrTooOld) {
+		t.Errorf("AddInput(0, -1, 5) = %v, want %v", err, ErrTooOld)
+	}
+	if s.Rollbacks() != 0 {
+		t.Error("negative tick caused a rollback")
+	}
+}
+
+func TestPrediction(t *testing.T) {
+	s := NewSession(2, 8, 0, counter)
+	s.AddInput(0, 0, 1)
+	s.AddInput(1, 0, 10)
+	s.Advance()
+	// Tick 1 and 2 predict the last known inputs
+	s.AddInput(0, 1, 1)
+	s.Advance()
+	s.AddInput(0, 2, 1)
+	s.Advance()
+	if got := *s.State(); got != 33 || s.Rollbacks() != 0 {
+		t.Fatalf("state %d, rollbacks %d, want 33, 0", got, s.Rollbacks())
+	}
+	// A matching late input does not roll back
+	s.AddInput(1, 1, 10)
+	if got := *s.State(); got != 33 || s.Rollbacks() != 0 {
+		t.Fatalf("state %d, rollbacks %d, want 33, 0", got, s.Rollbacks())
+	}
+	// A mispredicted late input rolls back and also changes the prediction of tick 2
+	s.AddInput(1, 2, 0)
+	if got := *s.State(); got != 23 || s.Rollbacks() != 1 {
+		t.Fatalf("state %d, rollbacks %d, want 23, 1", got, s.Rollbacks())
+	}
+}
@@ -0,0 +1,320 @@
+package tilecollider
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+)
+
+// SnapshotVersion is the version of the binary snapshot format written by WriteSnapshot
+const SnapshotVersion = 1
+
+var snapshotMagic = [4]byte{'T', 'C', 'O', 'L'}
+
+// maxSnapshotTiles limits the map size accepted by ReadSnapshot. Tiles are run-length encoded,
+// so a few bytes can declare any size and the input length cannot bound the allocation.
+const maxSnapshotTiles = 1 << 28
+
+var (
+	ErrSnapshotFormat  = errors.New("tilecollider: invalid snapshot")
+	ErrSnapshotVersion = errors.New("tilecollider: unsupported snapshot version")
+	ErrSnapshotType    = errors.New("tilecollider: snapshot tile type mismatch")
+	ErrUnboundedSource = errors.New("tilecollider: cannot snapshot an unbounded or offset tile source")
+)
+
+// Body stores the state of a moving rectangle for snapshots
+type Body struct {
+	Rect [4]float64 // X, Y, width and height
+	Vel  [2]float64 // X and Y velocity
+}
+
+// WriteSnapshot writes the collision state and the bodies in a compact versioned binary format.
+//
+// The snapshot contains the tiles, TileSize, NonSolidTileID, StaticCheck and Fluids. SolidFunc and watchers are not stored.
+// The tile source must have bounds starting at 0,0.
+func (c *ColliderOf[T, F]) WriteSnapshot(w io.Writer, bodies []Body) error {
+	minX, minY, maxX, maxY := c.bounds()
+	if minX != 0 || minY != 0 || maxX == math.MaxInt || maxY == math.MaxInt {
+		return ErrUnboundedSource
+	}
+	bits, signed := intKind[T]()
+
+	var buf bytes.Buffer
+	buf.Write(snapshotMagic[:])
+	buf.WriteByte(SnapshotVersion)
+	buf.WriteByte(byte(bits))
+	buf.WriteByte(boolByte(signed))
+	buf.WriteByte(boolByte(c.StaticCheck))
+	putVarint(&buf, int64(c.TileSize[0]))
+	putVarint(&buf, int64(c.TileSize[1]))
+	putTile(&buf, c.NonSolidTileID)
+
+	putUvarint(&buf, uint64(len(c.Fluids)))
+	for _, id := range slices.Sorted(maps.Keys(c.Fluids)) {
+		f := c.Fluids[id]
+		putTile(&buf, id)
+		putFloat(&buf, f.Buoyancy)
+		putFloat(&buf, f.Drag)
+	}
+
+	// Tiles are run-length encoded in row-major order. Maps without columns are stored as 0x0.
+	if maxX == 0 {
+		maxY = 0
+	}
+	putUvarint(&buf, uint64(maxX))
+	putUvarint(&buf, uint64(maxY))
+	var run uint64
+	var last T
+	for y := 0; y < maxY; y++ {
+		for x := 0; x < maxX; x++ {
+			id := c.tile(x, y)
+			if run > 0 && id != last {
+				putUvarint(&buf, run)
+				putTile(&buf, last)
+				run = 0
+			}
+			last = id
+			run++
+		}
+	}
+	if run > 0 {
+		putUvarint(&buf, run)
+		putTile(&buf, last)
+	}
+
+	putUvarint(&buf, uint64(len(bodies)))
+	for _, b := range bodies {
+		for _, v := range b.Rect {
+			putFloat(&buf, v)
+		}
+		for _, v := range b.Vel {
+			putFloat(&buf, v)
+		}
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ReadSnapshot restores the collision state written by WriteSnapshot and returns the bodies.
+//
+// If Source is a TileSetter with the same bounds, tiles are written into it; otherwise TileMap is replaced and Source is set to nil.
+// Watchers receive the whole map as a dirty region. If r is not an io.ByteReader, ReadSnapshot may read past the end of the snapshot.
+// Snapshots of more than 2^28 tiles are rejected.
+func (c *ColliderOf[T, F]) ReadSnapshot(r io.Reader) ([]Body, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	d := &snapshotDecoder{r: br}
+
+	var magic [4]byte
+	for i := range magic {
+		magic[i] = d.byte()
+	}
+	if d.err == nil && magic != snapshotMagic {
+		return nil, ErrSnapshotFormat
+	}
+	if version := d.byte(); d.err == nil && version != SnapshotVersion {
+		return nil, fmt.Errorf("%w: %d", ErrSnapshotVersion, version)
+	}
+	bits, signed := intKind[T]()
+	snapBits, snapSigned := int(d.byte()), d.byte() != 0
+	if d.err == nil && (snapBits != bits || snapSigned != signed) {
+		return nil, fmt.Errorf("%w: snapshot has %d-bit signed=%v tiles", ErrSnapshotType, snapBits, snapSigned)
+	}
+
+	staticCheck := d.byte() != 0
+	tileSize := [2]int{int(d.varint()), int(d.varint())}
+	if d.err == nil && (tileSize[0] <= 0 || tileSize[1] <= 0) {
+		return nil, fmt.Errorf("%w: tile size %dx%d", ErrSnapshotFormat, tileSize[0], tileSize[1])
+	}
+	nonSolid := readTile[T](d)
+
+	var fluids map[T]Fluid
+	if n := d.count(); n > 0 {
+		fluids = make(map[T]Fluid, n)
+		for range n {
+			id := readTile[T](d)
+			fluids[id] = Fluid{Buoyancy: d.float(), Drag: d.float()}
+		}
+	}
+
+	width, height := d.count(), d.count()
+	if d.err == nil && ((width == 0 && height != 0) || (width > 0 && height > maxSnapshotTiles/width)) {
+		return nil, fmt.Errorf("%w: map size %dx%d", ErrSnapshotFormat, width, height)
+	}
+	tiles := make([]T, 0, min(width*height, 1<<20))
+	for d.err == nil && len(tiles) < width*height {
+		run := d.count()
+		id := readTile[T](d)
+		if run == 0 || run > width*height-len(tiles) {
+			d.fail()
+			break
+		}
+		for range run {
+			tiles = append(tiles, id)
+		}
+	}
+
+	n := d.count()
+	bodies := make([]Body, 0, min(n, 1<<16))
+	for i := 0; d.err == nil && i < n; i++ {
+		var b Body
+		for j := range b.Rect {
+			b.Rect[j] = d.float()
+		}
+		for j := range b.Vel {
+			b.Vel[j] = d.float()
+		}
+		bodies = append(bodies, b)
+	}
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	c.StaticCheck = staticCheck
+	c.TileSize = tileSize
+	c.NonSolidTileID = nonSolid
+	c.Fluids = fluids
+
+	setter, ok := c.Source.(TileSetter[T])
+	if ok {
+		minX, minY, maxX, maxY := setter.Bounds()
+		ok = minX == 0 && minY == 0 && maxX == width && maxY == height
+	}
+	if ok {
+		for i, id := range tiles {
+			setter.Set(i%width, i/width, id)
+		}
+	} else {
+		c.Source = nil
+		c.TileMap = make([][]T, height)
+		for y := range c.TileMap {
+			c.TileMap[y] = tiles[y*width : (y+1)*width : (y+1)*width]
+		}
+	}
+
+	for _, w := range c.watchers {
+		w.addDirty([4]int{0, 0, width, height})
+	}
+	return bodies, nil
+}
+
+// MarshalBinary encodes the collision state without bodies. See WriteSnapshot.
+func (c *ColliderOf[T, F]) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := c.WriteSnapshot(&buf, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary restores the collision state encoded by MarshalBinary. See ReadSnapshot.
+func (c *ColliderOf[T, F]) UnmarshalBinary(data []byte) error {
+	_, err := c.ReadSnapshot(bytes.NewReader(data))
+	return err
+}
+
+// snapshotDecoder reads snapshot values and keeps the first error
+type snapshotDecoder struct {
+	r   io.ByteReader
+	err error
+}
+
+func (d *snapshotDecoder) fail() {
+	if d.err == nil {
+		d.err = ErrSnapshotFormat
+	}
+}
+
+func (d *snapshotDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	b, err := d.r.ReadByte()
+	if err != nil {
+		d.fail()
+	}
+	return b
+}
+
+func (d *snapshotDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		d.fail()
+	}
+	return v
+}
+
+func (d *snapshotDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, err := binary.ReadVarint(d.r)
+	if err != nil {
+		d.fail()
+	}
+	return v
+}
+
+// count reads a non-negative length
+func (d *snapshotDecoder) count() int {
+	v := d.uvarint()
+	if v > math.MaxInt32 {
+		d.fail()
+		return 0
+	}
+	return int(v)
+}
+
+func (d *snapshotDecoder) float() float64 {
+	var v uint64
+	for i := range 8 {
+		v |= uint64(d.byte()) << (8 * i)
+	}
+	return math.Float64frombits(v)
+}
+
+func readTile[T Integer](d *snapshotDecoder) T {
+	if _, signed := intKind[T](); signed {
+		return T(d.varint())
+	}
+	return T(d.uvarint())
+}
+
+func putTile[T Integer](buf *bytes.Buffer, id T) {
+	if _, signed := intKind[T](); signed {
+		putVarint(buf, int64(id))
+	} else {
+		putUvarint(buf, uint64(id))
+	}
+}
+
+func putUvarint(buf *bytes.Buffer, v uint64) {
+	buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func putVarint(buf *bytes.Buffer, v int64) {
+	buf.Write(binary.AppendVarint(nil, v))
+}
+
+func putFloat(buf *bytes.Buffer, v float64) {
+	buf.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)))
+}
+
+func boolByte(b bool) byte {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,215 @@
+package tilecollider
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func snapshotCollider() (*Collider[uint8], []Body) {
+	c := NewCollider(randMap(rand.New(rand.NewSource(5)), 40, 30), 16, 12)
+	c.StaticCheck = true
+	c.NonSolidTileID = 3
+	c.Fluids = map[uint8]Fluid{2: {Buoyancy: 0.1, Drag: 0.3}, 1: {Drag: 1}}
+	bodies := []Body{
+		{Rect: [4]float64{1.1, 2, 3, 4}, Vel: [2]float64{-0.3, 1e-300}},
+		{Rect: [4]float64{math.Copysign(0, -1), math.Inf(1), math.NaN(), math.MaxFloat64}, Vel: [2]float64{math.SmallestNonzeroFloat64, -1}},
+	}
+	return c, bodies
+}
+
+func bodyBits(bodies []Body) [][6]uint64 {
+	out := make([][6]uint64, len(bodies))
+	for i, b := range bodies {
+		for j, v := range append(b.Rect[:], b.Vel[:]...) {
+			out[i][j] = math.Float64bits(v)
+		}
+	}
+	return out
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	c, bodies := snapshotCollider()
+	var buf bytes.Buffer
+	if err := c.WriteSnapshot(&buf, bodies); err != nil {
+		t.Fatal(err)
+	}
+	data := bytes.Clone(buf.Bytes())
+
+	d := NewCollider[uint8](nil, 1, 1)
+	got, err := d.ReadSnapshot(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(bodyBits(got), bodyBits(bodies)) {
+		t.Errorf("bodies = %v, want %v", got, bodies)
+	}
+	if !reflect.DeepEqual(d.TileMap, c.TileMap) {
+		t.Error("tiles differ")
+	}
+	if d.TileSize != c.TileSize || d.NonSolidTileID != c.NonSolidTileID || d.StaticCheck != c.StaticCheck {
+		t.Errorf("config = %v %v %v, want %v %v %v", d.TileSize, d.NonSolidTileID, d.StaticCheck, c.TileSize, c.NonSolidTileID, c.StaticCheck)
+	}
+	if !reflect.DeepEqual(d.Fluids, c.Fluids) {
+		t.Errorf("Fluids = %v, want %v", d.Fluids, c.Fluids)
+	}
+
+	// Writing the restored state again must produce the same bytes
+	buf.Reset()
+	if err := d.WriteSnapshot(&buf, got); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("snapshot is not bit-exact after a round trip")
+	}
+}
+
+func TestSnapshotIntoSource(t *testing.T) {
+	c, _ := snapshotCollider()
+	data, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid := NewGrid[uint8](40, 30)
+	d := NewColliderSource[uint8](grid, 1, 1)
+	w := d.Watch(nil)
+	if err := d.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if d.Source != TileSource[uint8](grid) {
+		t.Fatal("Source was replaced")
+	}
+	if !reflect.DeepEqual(grid.Slice(), c.TileMap) {
+		t.Error("tiles differ")
+	}
+	if got, want := w.Drain(), [][4]int{{0, 0, 40, 30}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("dirty = %v, want %v", got, want)
+	}
+
+	// A source with other bounds is replaced by TileMap
+	e := NewColliderSource[uint8](NewGrid[uint8](4, 4), 1, 1)
+	if err := e.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if e.Source != nil || !reflect.DeepEqual(e.TileMap, c.TileMap) {
+		t.Error("tiles were not restored into TileMap")
+	}
+}
+
+func TestSnapshotSigned(t *testing.T) {
+	m := [][]int64{{-5, 1 << 62}, {0, math.MinInt64}}
+	c := NewCollider(m, 2, 2)
+	c.NonSolidTileID = -1
+	data, err := c.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := NewCollider[int64](nil, 1, 1)
+	if err := d.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(d.TileMap, m) || d.NonSolidTileID != -1 {
+		t.Errorf("got %v %v", d.TileMap, d.NonSolidTileID)
+	}
+}
+
+func TestSnapshotRejects(t *testing.T) {
+	c, bodies := snapshotCollider()
+	var buf bytes.Buffer
+	if err := c.WriteSnapshot(&buf, bodies); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+
+	badMagic := bytes.Clone(data)
+	badMagic[0] = 'X'
+	badVersion := bytes.Clone(data)
+	badVersion[4] = SnapshotVersion + 1
+
+	tests := []struct {
+		name string
+		data []byte
+		read func([]byte) error
+		want error
+	}{
+		{"magic", badMagic, unmarshalInto[uint8], ErrSnapshotFormat},
+		{"version", badVersion, unmarshalInto[uint8], ErrSnapshotVersion},
+		{"signed type", data, unmarshalInto[int8], ErrSnapshotType},
+		{"wider type", data, unmarshalInto[uint16], ErrSnapshotType},
+		{"empty", nil, unmarshalInto[uint8], ErrSnapshotFormat},
+		{"valid crafted", craftSnapshot(16, 16, 3, 2), unmarshalInto[uint8], nil},
+		{"zero width", craftSnapshot(16, 16, 0, math.MaxInt32), unmarshalInto[uint8], ErrSnapshotFormat},
+		{"too many tiles", craftSnapshot(16, 16, 1<<20, 1<<20), unmarshalInto[uint8], ErrSnapshotFormat},
+		{"zero tile width", craftSnapshot(0, 16, 1, 1), unmarshalInto[uint8], ErrSnapshotFormat},
+		{"negative tile height", craftSnapshot(16, -4, 1, 1), unmarshalInto[uint8], ErrSnapshotFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.read(tt.data); !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// craftSnapshot returns a uint8 snapshot header with the given tile and map sizes, followed by a single run of empty tiles
+func craftSnapshot(tileW, tileH int64, width, height uint64) []byte {
+	data := append(snapshotMagic[:], SnapshotVersion, 8, 0, 0)
+	data = binary.AppendVarint(data, tileW)
+	data = binary.AppendVarint(data, tileH)
+	data = append(data, 0, 0) // NonSolidTileID and no fluids
+	data = binary.AppendUvarint(data, width)
+	data = binary.AppendUvarint(data, height)
+	data = binary.AppendUvarint(data, width*height)
+	return append(data, 0, 0) // Tile ID and no bodies
+}
+
+func unmarshalInto[T Integer](data []byte) error {
+	return NewCollider[T](nil, 1, 1).UnmarshalBinary(data)
+}
+
+func TestSnapshotTruncated(t *testing.T) {
+	c, bodies := snapshotCollider()
+	var buf bytes.Buffer
+	if err := c.WriteSnapshot(&buf, bodies); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	d := NewCollider([][]uint8{{7}}, 5, 5)
+	for i := range len(data) {
+		if _, err := d.ReadSnapshot(bytes.NewReader(data[:i])); !errors.Is(err, ErrSnapshotFormat) {
+			t.Fatalf("%d of %d bytes: err = %v, want %v", i, len(data), err, ErrSnapshotFormat)
+		}
+	}
+	// A failed read leaves the collider untouched
+	if !reflect.DeepEqual(d.TileMap, [][]uint8{{7}}) || d.TileSize != [2]int{5, 5} {
+		t.Error("collider was modified by a failed read")
+	}
+}
+
+func TestSnapshotUnboundedSource(t *testing.T) {
+	sources := map[string]TileSource[uint8]{
+		"chunk":  NewChunkMap[uint8](4, 4, 0, nil),
+		"offset": offsetSource{NewGrid[uint8](4, 4)},
+	}
+	for name, src := range sources {
+		t.Run(name, func(t *testing.T) {
+			c := NewColliderSource(src, 1, 1)
+			if _, err := c.MarshalBinary(); !errors.Is(err, ErrUnboundedSource) {
+				t.Errorf("err = %v, want %v", err, ErrUnboundedSource)
+			}
+		})
+	}
+}
+
+// offsetSource shifts the bounds of a source by one tile
+type offsetSource struct{ *Grid[uint8] }
+
+func (s offsetSource) Bounds() (minX, minY, maxX, maxY int) {
+	minX, minY, maxX, maxY = s.Grid.Bounds()
+	return minX + 1, minY + 1, maxX + 1, maxY + 1
+}
@@ -0,0 +1,19 @@
+package tilecollider
+
+import "testing"
+
+func TestIsSolid(t *testing.T) {
+	c := NewCollider([][]int{{0}}, 16, 16)
+	c.NonSolidTileID = 5
+	if c.IsSolid(5) || !c.IsSolid(0) {
+		t.Error("NonSolidTileID not applied")
+	}
+	c.Fluids = map[int]Fluid{3: {}}
+	if c.IsSolid(3) {
+		t.Error("fluid tile is solid")
+	}
+	c.SolidFunc = func(id int) bool { return id > 1 }
+	if c.IsSolid(1) || !c.IsSolid(2) || c.IsSolid(3) {
+		t.Error("SolidFunc not applied or overrides fluids")
+	}
+}
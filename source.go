@@ -0,0 +1,37 @@
+package tilecollider
+
+// TileSource is a tile storage that can be used by the Collider instead of a [][]T tilemap
+type TileSource[T Integer] interface {
+	// Get returns the tile ID at the given tile coordinates. It is only called with coordinates inside Bounds.
+	Get(x, y int) T
+	// Bounds returns the tile coordinate bounds of the storage. max values are exclusive.
+	Bounds() (minX, minY, maxX, maxY int)
+}
+
+// SliceSource is a TileSource backed by a [][]T tilemap
+type SliceSource[T Integer] [][]T
+
+// Get returns the tile ID at the given tile coordinates
+func (s SliceSource[T]) Get(x, y int) T {
+	return s[y][x]
+}
+
+// Bounds returns the tile coordinate bounds of the tilemap
+func (s SliceSource[T]) Bounds() (minX, minY, maxX, maxY int) {
+	if len(s) == 0 {
+		return 0, 0, 0, 0
+	}
+	return 0, 0, len(s[0]), len(s)
+}
+
+// Set sets the tile ID at the given tile coordinates
+func (s SliceSource[T]) Set(x, y int, id T) {
+	s[y][x] = id
+}
+
+// TileSetter is a TileSource whose tiles can be changed
+type TileSetter[T Integer] interface {
+	TileSource[T]
+	// Set sets the tile ID at the given tile coordinates. It is only called with coordinates inside Bounds.
+	Set(x, y int, id T)
+}
@@ -0,0 +1,9 @@
+package tilecollider
+
+import "testing"
+
+func TestSliceSourceMatchesReference(t *testing.T) {
+	checkEquivalent(t, 20000, func(m [][]uint8, tw, th int) *Collider[uint8] {
+		return NewColliderSource[uint8](SliceSource[uint8](m), tw, th)
+	})
+}
@@ -0,0 +1,63 @@
+package tilecollider
+
+// SparseMap is a TileSource that only stores non-empty tiles. Unset tiles read as Empty.
+type SparseMap[T Integer] struct {
+	Tiles  map[[2]int]T // Non-empty tiles keyed by X,Y tile coordinates
+	Width  int          // Number of tiles in a row
+	Height int          // Number of rows
+	Empty  T            // ID of unset tiles. Must match the NonSolidTileID of the Collider, see NewColliderSparse.
+}
+
+// NewSparseMap creates a new empty sparse tilemap with the given size
+func NewSparseMap[T Integer](width, height int, empty T) *SparseMap[T] {
+	return &SparseMap[T]{
+		Tiles:  make(map[[2]int]T),
+		Width:  width,
+		Height: height,
+		Empty:  empty,
+	}
+}
+
+// SparseFromSlice creates a new sparse tilemap from the non-empty tiles of a [][]T tilemap
+func SparseFromSlice[T Integer](tileMap [][]T, empty T) *SparseMap[T] {
+	m := NewSparseMap(0, len(tileMap), empty)
+	if len(tileMap) > 0 {
+		m.Width = len(tileMap[0])
+	}
+	for y, row := range tileMap {
+		for x, id := range row {
+			m.Set(x, y, id)
+		}
+	}
+	return m
+}
+
+// NewColliderSparse creates a new tile collider that reads tiles from the sparse tilemap.
+// The NonSolidTileID of the collider is set to m.Empty so that unset tiles are never solid.
+func NewColliderSparse[T Integer](m *SparseMap[T], tileWidth, tileHeight int) *Collider[T] {
+	c := NewColliderSource[T](m, tileWidth, tileHeight)
+	c.NonSolidTileID = m.Empty
+	return c
+}
+
+// Get returns the tile ID at the given tile coordinates
+func (m *SparseMap[T]) Get(x, y int) T {
+	if id, ok := m.Tiles[[2]int{x, y}]; ok {
+		return id
+	}
+	return m.Empty
+}
+
+// Set sets the tile ID at the given tile coordinates. Setting Empty removes the tile.
+func (m *SparseMap[T]) Set(x, y int, id T) {
+	if id == m.Empty {
+		delete(m.Tiles, [2]int{x, y})
+		return
+	}
+	m.Tiles[[2]int{x, y}] = id
+}
+
+// Bounds returns the tile coordinate bounds of the tilemap
+func (m *SparseMap[T]) Bounds() (minX, minY, maxX, maxY int) {
+	return 0, 0, m.Width, m.Height
+}
@@ -0,0 +1,35 @@
+package tilecollider
+
+import "testing"
+
+func TestSparseMatchesReference(t *testing.T) {
+	checkEquivalent(t, 20000, func(m [][]uint8, tw, th int) *Collider[uint8] {
+		return NewColliderSparse(SparseFromSlice(m, 0), tw, th)
+	})
+}
+
+func TestSparseEmptyIsNonSolid(t *testing.T) {
+	m := NewSparseMap(4, 4, 5)
+	m.Set(2, 1, 1)
+	c := NewColliderSparse(m, 16, 16)
+	if c.NonSolidTileID != 5 {
+		t.Fatalf("NonSolidTileID is %d, want 5", c.NonSolidTileID)
+	}
+	if len(m.Tiles) != 1 {
+		t.Fatalf("stored %d tiles, want 1", len(m.Tiles))
+	}
+
+	// Moving right through unset tiles stops at the only solid tile
+	dx, _ := c.Collide(0, 16, 16, 16, 40, 0, nil)
+	if dx != 16 {
+		t.Errorf("got dx %v, want 16", dx)
+	}
+	if len(c.Collisions) != 1 || c.Collisions[0].TileCoords != [2]int{2, 1} {
+		t.Errorf("got collisions %v", c.Collisions)
+	}
+
+	m.Set(2, 1, 5)
+	if len(m.Tiles) != 0 {
+		t.Error("setting Empty does not remove the tile")
+	}
+}
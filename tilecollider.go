@@ -10,6 +10,9 @@ import (
 // Integer is a constraint that matches any integer type.
 type Integer = constraints.Integer
 
+// Float is a constraint that matches any floating-point type.
+type Float = constraints.Float
+
 // CollisionInfo stores information about a collision with a tile
 type CollisionInfo[T Integer] struct {
 	TileID     T      // ID of the collided tile
@@ -17,15 +20,23 @@ type CollisionInfo[T Integer] struct {
 	Normal     [2]int // Normal vector of the collision (-1/0/1)
 }
 
-// Collider handles collision detection between rectangles and a 2D tilemap
-type Collider[T Integer] struct {
+// ColliderOf handles collision detection between rectangles and a 2D tilemap, with coordinates of type F
+type ColliderOf[T Integer, F Float] struct {
 	Collisions     []CollisionInfo[T] // List of collisions from last check
 	TileSize       [2]int             // Width and height of tiles
 	TileMap        [][]T              // 2D grid of tile IDs
+	Source         TileSource[T]      // Optional tile storage. If set, it is used instead of TileMap.
 	NonSolidTileID T                  // Sets the ID of non-solid tiles. Defaults to 0.
 	StaticCheck    bool               // If true, always checks for static collisions. (no movement)
+	Fluids         map[T]Fluid        // Non-solid fluid tiles (water, lava...) keyed by tile ID
+	SolidFunc      func(id T) bool    // Optional rule that reports whether a tile ID is solid. Overrides NonSolidTileID.
+
+	watchers []*TileWatcher[T]
 }
 
+// Collider handles collision detection between rectangles and a 2D tilemap, with float64 coordinates
+type Collider[T Integer] = ColliderOf[T, float64]
+
 // NewCollider creates a new tile collider with the given tilemap and tile dimensions
 func NewCollider[T Integer](tileMap [][]T, tileWidth, tileHeight int) *Collider[T] {
 	return &Collider[T]{
@@ -34,45 +45,67 @@ func NewCollider[T Integer](tileMap [][]T, tileWidth, tileHeight int) *Collider[
 	}
 }
 
-// CollisionCallback is called when collisions occur, receiving collision info and final movement
-type CollisionCallback[T Integer] func([]CollisionInfo[T], float64, float64)
+// NewColliderOf creates a new tile collider with coordinates of type F (e.g. float32)
+func NewColliderOf[T Integer, F Float](tileMap [][]T, tileWidth, tileHeight int) *ColliderOf[T, F] {
+	return &ColliderOf[T, F]{
+		TileMap:  tileMap,
+		TileSize: [2]int{tileWidth, tileHeight},
+	}
+}
+
+// NewColliderSource creates a new tile collider that reads tiles from the given source
+func NewColliderSource[T Integer](source TileSource[T], tileWidth, tileHeight int) *Collider[T] {
+	return &Collider[T]{
+		Source:   source,
+		TileSize: [2]int{tileWidth, tileHeight},
+	}
+}
+
+// CollisionCallbackOf is called when collisions occur, receiving collision info and final movement
+type CollisionCallbackOf[T Integer, F Float] func([]CollisionInfo[T], F, F)
+
+// CollisionCallback is a CollisionCallbackOf with float64 movement
+type CollisionCallback[T Integer] = CollisionCallbackOf[T, float64]
 
 // Collide checks for collisions when moving a rectangle and returns the allowed movement
-func (c *Collider[T]) Collide(rectX, rectY, rectW, rectH, moveX, moveY float64, onCollide CollisionCallback[T]) (float64, float64) {
+func (c *ColliderOf[T, F]) Collide(rectX, rectY, rectW, rectH, moveX, moveY F, onCollide CollisionCallbackOf[T, F]) (F, F) {
 
 	c.Collisions = c.Collisions[:0]
 
 	if moveX == 0 && moveY == 0 {
 		if c.StaticCheck {
 			// Static collision test
-			playerTop := int(math.Floor(rectY / float64(c.TileSize[1])))
-			playerBottom := int(math.Ceil((rectY+rectH)/float64(c.TileSize[1]))) - 1
-			playerLeft := int(math.Floor(rectX / float64(c.TileSize[0])))
-			playerRight := int(math.Ceil((rectX+rectW)/float64(c.TileSize[0]))) - 1
+			minX, minY, maxX, maxY := c.bounds()
+			grid, plain := c.fastPath()
+			playerLeft, playerTop, playerRight, playerBottom := c.cellRange(rectX, rectY, rectW, rectH)
 
-			minPenetration := math.MaxFloat64
-			var resolveX, resolveY float64
+			minPenetration := F(math.Inf(1))
+			var resolveX, resolveY F
 
 			for y := playerTop; y <= playerBottom; y++ {
-				if y < 0 || y >= len(c.TileMap) {
+				if y < minY || y >= maxY {
 					continue
 				}
 				for x := playerLeft; x <= playerRight; x++ {
-					if x < 0 || x >= len(c.TileMap[0]) {
+					if x < minX || x >= maxX {
 						continue
 					}
-					if c.TileMap[y][x] != c.NonSolidTileID {
+					id, solid, ok := c.solidFast(grid, plain, x, y)
+					if !ok {
+						id, solid = c.solidAt(x, y)
+					}
+					if solid {
 						// Calculate overlap on each axis
-						tileLeft := float64(x * c.TileSize[0])
-						tileTop := float64(y * c.TileSize[1])
+						tileLeft := F(x * c.TileSize[0])
+						tileTop := F(y * c.TileSize[1])
 
-						overlapX := min(rectX+rectW-tileLeft, tileLeft+float64(c.TileSize[0])-rectX)
-						overlapY := min(rectY+rectH-tileTop, tileTop+float64(c.TileSize[1])-rectY)
+						overlapX := min(rectX+rectW-tileLeft, tileLeft+F(c.TileSize[0])-rectX)
+						overlapY := min(rectY+rectH-tileTop, tileTop+F(c.TileSize[1])-rectY)
 
 						// Choose smallest penetration
 						if overlapX < overlapY && overlapX < minPenetration {
 							minPenetration = overlapX
-							if rectX+rectW/2 < tileLeft+float64(c.TileSize[0])/2 {
+							if rectX+rectW/2 < tileLeft+F(c.TileSize[0])/2 {
 								resolveX = -overlapX
 								resolveY = 0
 							} else {
@@ -81,7 +114,7 @@ func (c *Collider[T]) Collide(rectX, rectY, rectW, rectH, moveX, moveY float64,
 							}
 						} else if overlapY < minPenetration {
 							minPenetration = overlapY
-							if rectY+rectH/2 < tileTop+float64(c.TileSize[1])/2 {
+							if rectY+rectH/2 < tileTop+F(c.TileSize[1])/2 {
 								resolveX = 0
 								resolveY = -overlapY
 							} else {
@@ -91,9 +124,9 @@ func (c *Collider[T]) Collide(rectX, rectY, rectW, rectH, moveX, moveY float64,
 						}
 
 						c.Collisions = append(c.Collisions, CollisionInfo[T]{
-							TileID:     c.TileMap[y][x],
+							TileID:     id,
 							TileCoords: [2]int{x, y},
-							Normal:     [2]int{int(math.Copysign(1, -resolveX)), int(math.Copysign(1, -resolveY))},
+							Normal:     [2]int{int(math.Copysign(1, -float64(resolveX))), int(math.Copysign(1, -float64(resolveY)))},
 						})
 					}
 				}
@@ -105,7 +138,7 @@ func (c *Collider[T]) Collide(rectX, rectY, rectW, rectH, moveX, moveY float64,
 		}
 	}
 
-	if math.Abs(moveX) > math.Abs(moveY) {
+	if abs(moveX) > abs(moveY) {
 		if moveX != 0 {
 			moveX = c.CollideX(rectX, rectY, rectW, rectH, moveX)
 		}
@@ -129,33 +162,39 @@ func (c *Collider[T]) Collide(rectX, rectY, rectW, rectH, moveX, moveY float64,
 }
 
 // CollideX checks for collisions along the X axis and returns the allowed X movement
-func (c *Collider[T]) CollideX(rectX, rectY, rectW, rectH, moveX float64) float64 {
+func (c *ColliderOf[T, F]) CollideX(rectX, rectY, rectW, rectH, moveX F) F {
 
-	checkLimit := max(1, int(math.Ceil(math.Abs(moveX)/float64(c.TileSize[0])))+1)
+	minX, minY, maxX, maxY := c.bounds()
+	grid, plain := c.fastPath()
+	checkLimit := max(1, ceil(abs(moveX)/F(c.TileSize[0]))+1)
 
-	playerTop := int(math.Floor(rectY / float64(c.TileSize[1])))
-	playerBottom := int(math.Ceil((rectY+rectH)/float64(c.TileSize[1]))) - 1
+	playerTop := floor(rectY / F(c.TileSize[1]))
+	playerBottom := ceil((rectY+rectH)/F(c.TileSize[1])) - 1
 
 	if moveX > 0 {
-		startX := int(math.Floor((rectX + rectW) / float64(c.TileSize[0])))
+		startX := floor((rectX + rectW) / F(c.TileSize[0]))
 		endX := startX + checkLimit
-		endX = min(endX, len(c.TileMap[0]))
+		endX = min(endX, maxX)
 
 		for y := playerTop; y <= playerBottom; y++ {
-			if y < 0 || y >= len(c.TileMap) {
+			if y < minY || y >= maxY {
 				continue
 			}
 			for x := startX; x < endX; x++ {
-				if x < 0 || x >= len(c.TileMap[0]) {
+				if x < minX || x >= maxX {
 					continue
 				}
-				if c.TileMap[y][x] != c.NonSolidTileID {
-					tileLeft := float64(x * c.TileSize[0])
+				id, solid, ok := c.solidFast(grid, plain, x, y)
+				if !ok {
+					id, solid = c.solidAt(x, y)
+				}
+				if solid {
+					tileLeft := F(x * c.TileSize[0])
 					collision := tileLeft - (rectX + rectW)
 					if collision <= moveX {
 						moveX = collision
 						c.Collisions = append(c.Collisions, CollisionInfo[T]{
-							TileID:     c.TileMap[y][x],
+							TileID:     id,
 							TileCoords: [2]int{x, y},
 							Normal:     [2]int{-1, 0},
 						})
@@ -166,25 +205,29 @@ func (c *Collider[T]) CollideX(rectX, rectY, rectW, rectH, moveX float64) float6
 	}
 
 	if moveX < 0 {
-		endX := int(math.Floor(rectX / float64(c.TileSize[0])))
+		endX := floor(rectX / F(c.TileSize[0]))
 		startX := endX - checkLimit
-		startX = max(startX, 0)
+		startX = max(startX, minX)
 
 		for y := playerTop; y <= playerBottom; y++ {
-			if y < 0 || y >= len(c.TileMap) {
+			if y < minY || y >= maxY {
 				continue
 			}
 			for x := startX; x <= endX; x++ {
-				if x < 0 || x >= len(c.TileMap[0]) {
+				if x < minX || x >= maxX {
 					continue
 				}
-				if c.TileMap[y][x] != c.NonSolidTileID {
-					tileRight := float64((x + 1) * c.TileSize[0])
+				id, solid, ok := c.solidFast(grid, plain, x, y)
+				if !ok {
+					id, solid = c.solidAt(x, y)
+				}
+				if solid {
+					tileRight := F((x + 1) * c.TileSize[0])
 					collision := tileRight - rectX
 					if collision >= moveX {
 						moveX = collision
 						c.Collisions = append(c.Collisions, CollisionInfo[T]{
-							TileID:     c.TileMap[y][x],
+							TileID:     id,
 							TileCoords: [2]int{x, y},
 							Normal:     [2]int{1, 0},
 						})
@@ -198,33 +241,39 @@ func (c *Collider[T]) CollideX(rectX, rectY, rectW, rectH, moveX float64) float6
 }
 
 // CollideY checks for collisions along the Y axis and returns the allowed Y movement
-func (c *Collider[T]) CollideY(rectX, rectY, rectW, rectH, moveY float64) float64 {
+func (c *ColliderOf[T, F]) CollideY(rectX, rectY, rectW, rectH, moveY F) F {
 
-	checkLimit := max(1, int(math.Ceil(math.Abs(moveY)/float64(c.TileSize[1])))+1)
+	minX, minY, maxX, maxY := c.bounds()
+	grid, plain := c.fastPath()
+	checkLimit := max(1, ceil(abs(moveY)/F(c.TileSize[1]))+1)
 
-	playerLeft := int(math.Floor(rectX / float64(c.TileSize[0])))
-	playerRight := int(math.Ceil((rectX+rectW)/float64(c.TileSize[0]))) - 1
+	playerLeft := floor(rectX / F(c.TileSize[0]))
+	playerRight := ceil((rectX+rectW)/F(c.TileSize[0])) - 1
 
 	if moveY > 0 {
-		startY := int(math.Floor((rectY + rectH) / float64(c.TileSize[1])))
+		startY := floor((rectY + rectH) / F(c.TileSize[1]))
 		endY := startY + checkLimit
-		endY = min(endY, len(c.TileMap))
+		endY = min(endY, maxY)
 
 		for x := playerLeft; x <= playerRight; x++ {
-			if x < 0 || x >= len(c.TileMap[0]) {
+			if x < minX || x >= maxX {
 				continue
 			}
 			for y := startY; y < endY; y++ {
-				if y < 0 || y >= len(c.TileMap) {
+				if y < minY || y >= maxY {
 					continue
 				}
-				if c.TileMap[y][x] != c.NonSolidTileID {
-					tileTop := float64(y * c.TileSize[1])
+				id, solid, ok := c.solidFast(grid, plain, x, y)
+				if !ok {
+					id, solid = c.solidAt(x, y)
+				}
+				if solid {
+					tileTop := F(y * c.TileSize[1])
 					collision := tileTop - (rectY + rectH)
 					if collision <= moveY {
 						moveY = collision
 						c.Collisions = append(c.Collisions, CollisionInfo[T]{
-							TileID:     c.TileMap[y][x],
+							TileID:     id,
 							TileCoords: [2]int{x, y},
 							Normal:     [2]int{0, -1},
 						})
@@ -235,25 +284,29 @@ func (c *Collider[T]) CollideY(rectX, rectY, rectW, rectH, moveY float64) float6
 	}
 
 	if moveY < 0 {
-		endY := int(math.Floor(rectY / float64(c.TileSize[1])))
+		endY := floor(rectY / F(c.TileSize[1]))
 		startY := endY - checkLimit
-		startY = max(startY, 0)
+		startY = max(startY, minY)
 
 		for x := playerLeft; x <= playerRight; x++ {
-			if x < 0 || x >= len(c.TileMap[0]) {
+			if x < minX || x >= maxX {
 				continue
 			}
 			for y := startY; y <= endY; y++ {
-				if y < 0 || y >= len(c.TileMap) {
+				if y < minY || y >= maxY {
 					continue
 				}
-				if c.TileMap[y][x] != c.NonSolidTileID {
-					tileBottom := float64((y + 1) * c.TileSize[1])
+				id, solid, ok := c.solidFast(grid, plain, x, y)
+				if !ok {
+					id, solid = c.solidAt(x, y)
+				}
+				if solid {
+					tileBottom := F((y + 1) * c.TileSize[1])
 					collision := tileBottom - rectY
 					if collision >= moveY {
 						moveY = collision
 						c.Collisions = append(c.Collisions, CollisionInfo[T]{
-							TileID:     c.TileMap[y][x],
+							TileID:     id,
 							TileCoords: [2]int{x, y},
 							Normal:     [2]int{0, 1},
 						})
@@ -265,3 +318,114 @@ func (c *Collider[T]) CollideY(rectX, rectY, rectW, rectH, moveY float64) float6
 
 	return moveY
 }
+
+// Tile returns the tile ID at the given tile coordinates.
+// ok is false if the coordinates are outside the tilemap bounds.
+func (c *ColliderOf[T, F]) Tile(x, y int) (id T, ok bool) {
+	minX, minY, maxX, maxY := c.bounds()
+	if x < minX || x >= maxX || y < minY || y >= maxY {
+		return id, false
+	}
+	return c.tile(x, y), true
+}
+
+// Bounds returns the tile coordinate bounds of the tilemap. max values are exclusive.
+// Unbounded sources such as ChunkMap return math.MinInt and math.MaxInt.
+func (c *ColliderOf[T, F]) Bounds() (minX, minY, maxX, maxY int) {
+	return c.bounds()
+}
+
+// bounds returns the tile coordinate bounds of the tilemap
+func (c *ColliderOf[T, F]) bounds() (minX, minY, maxX, maxY int) {
+	if c.Source != nil {
+		return c.Source.Bounds()
+	}
+	if len(c.TileMap) == 0 {
+		return 0, 0, 0, 0
+	}
+	return 0, 0, len(c.TileMap[0]), len(c.TileMap)
+}
+
+// tile returns the tile ID at the given coordinates without bounds checking
+func (c *ColliderOf[T, F]) tile(x, y int) T {
+	switch s := c.Source.(type) {
+	case nil:
+		return c.TileMap[y][x]
+	case *Grid[T]:
+		return s.Data[y*s.Stride+x]
+	default:
+		return s.Get(x, y)
+	}
+}
+
+// IsSolid reports whether the tile ID blocks movement. Fluid tiles are never solid.
+func (c *ColliderOf[T, F]) IsSolid(id T) bool {
+	if c.SolidFunc != nil {
+		if !c.SolidFunc(id) {
+			return false
+		}
+	} else if id == c.NonSolidTileID {
+		return false
+	}
+	if len(c.Fluids) == 0 {
+		return true
+	}
+	_, fluid := c.Fluids[id]
+	return !fluid
+}
+
+// fastPath reports whether only NonSolidTileID decides solidity, so that tiles can be compared inline.
+// grid is set if tiles are read from a Grid and plain is set if they are read from TileMap.
+// It is computed once per call so that these paths stay a single comparison per tile.
+func (c *ColliderOf[T, F]) fastPath() (grid *Grid[T], plain bool) {
+	if len(c.Fluids) != 0 || c.SolidFunc != nil {
+		return nil, false
+	}
+	if g, ok := c.Source.(*Grid[T]); ok {
+		return g, false
+	}
+	return nil, c.Source == nil
+}
+
+// solidFast returns the tile ID at the coordinates and whether it is solid, without bounds checking.
+// grid and plain come from fastPath. ok is false if neither is set and solidAt must be used instead.
+// It is kept small enough to be inlined, so the fast paths stay a single comparison per tile.
+func (c *ColliderOf[T, F]) solidFast(grid *Grid[T], plain bool, x, y int) (id T, solid, ok bool) {
+	if grid != nil {
+		id = grid.Data[y*grid.Stride+x]
+	} else if plain {
+		id = c.TileMap[y][x]
+	} else {
+		return id, false, false
+	}
+	return id, id != c.NonSolidTileID, true
+}
+
+// solidAt returns the tile ID at the coordinates and whether it is solid, without bounds checking
+func (c *ColliderOf[T, F]) solidAt(x, y int) (T, bool) {
+	id := c.tile(x, y)
+	return id, c.IsSolid(id)
+}
+
+// cellRange returns the inclusive range of tile coordinates overlapped by the rectangle
+func (c *ColliderOf[T, F]) cellRange(rectX, rectY, rectW, rectH F) (left, top, right, bottom int) {
+	left = floor(rectX / F(c.TileSize[0]))
+	top = floor(rectY / F(c.TileSize[1]))
+	right = ceil((rectX+rectW)/F(c.TileSize[0])) - 1
+	bottom = ceil((rectY+rectH)/F(c.TileSize[1])) - 1
+	return
+}
+
+// floor returns the value rounded down to an int
+func floor[F Float](v F) int {
+	return int(math.Floor(float64(v)))
+}
+
+// ceil returns the value rounded up to an int
+func ceil[F Float](v F) int {
+	return int(math.Ceil(float64(v)))
+}
+
+func abs[F Float](v F) F {
+	return F(math.Abs(float64(v)))
+}
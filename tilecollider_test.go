@@ -0,0 +1,360 @@
+package tilecollider
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// refCollider is the original [][]T collider before fluids, tile sources and float types were added.
+// Tests compare every storage backend and coordinate type against it.
+type refCollider[T Integer] struct {
+	Collisions     []CollisionInfo[T]
+	TileSize       [2]int
+	TileMap        [][]T
+	NonSolidTileID T
+	StaticCheck    bool
+}
+
+// Collide is the original Collide
+func (c *refCollider[T]) Collide(rectX, rectY, rectW, rectH, moveX, moveY float64, onCollide func([]CollisionInfo[T], float64, float64)) (float64, float64) {
+
+	c.Collisions = c.Collisions[:0]
+
+	if moveX == 0 && moveY == 0 {
+		if c.StaticCheck {
+			// Static collision test
+			playerTop := int(math.Floor(rectY / float64(c.TileSize[1])))
+			playerBottom := int(math.Ceil((rectY+rectH)/float64(c.TileSize[1]))) - 1
+			playerLeft := int(math.Floor(rectX / float64(c.TileSize[0])))
+			playerRight := int(math.Ceil((rectX+rectW)/float64(c.TileSize[0]))) - 1
+
+			minPenetration := math.MaxFloat64
+			var resolveX, resolveY float64
+
+			for y := playerTop; y <= playerBottom; y++ {
+				if y < 0 || y >= len(c.TileMap) {
+					continue
+				}
+				for x := playerLeft; x <= playerRight; x++ {
+					if x < 0 || x >= len(c.TileMap[0]) {
+						continue
+					}
+					if c.TileMap[y][x] != c.NonSolidTileID {
+						// Calculate overlap on each axis
+						tileLeft := float64(x * c.TileSize[0])
+						tileTop := float64(y * c.TileSize[1])
+
+						overlapX := min(rectX+rectW-tileLeft, tileLeft+float64(c.TileSize[0])-rectX)
+						overlapY := min(rectY+rectH-tileTop, tileTop+float64(c.TileSize[1])-rectY)
+
+						// Choose smallest penetration
+						if overlapX < overlapY && overlapX < minPenetration {
+							minPenetration = overlapX
+							if rectX+rectW/2 < tileLeft+float64(c.TileSize[0])/2 {
+								resolveX = -overlapX
+								resolveY = 0
+							} else {
+								resolveX = overlapX
+								resolveY = 0
+							}
+						} else if overlapY < minPenetration {
+							minPenetration = overlapY
+							if rectY+rectH/2 < tileTop+float64(c.TileSize[1])/2 {
+								resolveX = 0
+								resolveY = -overlapY
+							} else {
+								resolveX = 0
+								resolveY = overlapY
+							}
+						}
+
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.TileMap[y][x],
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{int(math.Copysign(1, -resolveX)), int(math.Copysign(1, -resolveY))},
+						})
+					}
+				}
+			}
+
+			return resolveX, resolveY
+		} else {
+			return moveX, moveY
+		}
+	}
+
+	if math.Abs(moveX) > math.Abs(moveY) {
+		if moveX != 0 {
+			moveX = c.CollideX(rectX, rectY, rectW, rectH, moveX)
+		}
+		if moveY != 0 {
+			moveY = c.CollideY(rectX+moveX, rectY, rectW, rectH, moveY)
+		}
+	} else {
+		if moveY != 0 {
+			moveY = c.CollideY(rectX, rectY, rectW, rectH, moveY)
+		}
+		if moveX != 0 {
+			moveX = c.CollideX(rectX, rectY+moveY, rectW, rectH, moveX)
+		}
+	}
+
+	if onCollide != nil {
+		onCollide(c.Collisions, moveX, moveY)
+	}
+
+	return moveX, moveY
+}
+
+// CollideX is the original CollideX
+func (c *refCollider[T]) CollideX(rectX, rectY, rectW, rectH, moveX float64) float64 {
+
+	checkLimit := max(1, int(math.Ceil(math.Abs(moveX)/float64(c.TileSize[0])))+1)
+
+	playerTop := int(math.Floor(rectY / float64(c.TileSize[1])))
+	playerBottom := int(math.Ceil((rectY+rectH)/float64(c.TileSize[1]))) - 1
+
+	if moveX > 0 {
+		startX := int(math.Floor((rectX + rectW) / float64(c.TileSize[0])))
+		endX := startX + checkLimit
+		endX = min(endX, len(c.TileMap[0]))
+
+		for y := playerTop; y <= playerBottom; y++ {
+			if y < 0 || y >= len(c.TileMap) {
+				continue
+			}
+			for x := startX; x < endX; x++ {
+				if x < 0 || x >= len(c.TileMap[0]) {
+					continue
+				}
+				if c.TileMap[y][x] != c.NonSolidTileID {
+					tileLeft := float64(x * c.TileSize[0])
+					collision := tileLeft - (rectX + rectW)
+					if collision <= moveX {
+						moveX = collision
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.TileMap[y][x],
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{-1, 0},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if moveX < 0 {
+		endX := int(math.Floor(rectX / float64(c.TileSize[0])))
+		startX := endX - checkLimit
+		startX = max(startX, 0)
+
+		for y := playerTop; y <= playerBottom; y++ {
+			if y < 0 || y >= len(c.TileMap) {
+				continue
+			}
+			for x := startX; x <= endX; x++ {
+				if x < 0 || x >= len(c.TileMap[0]) {
+					continue
+				}
+				if c.TileMap[y][x] != c.NonSolidTileID {
+					tileRight := float64((x + 1) * c.TileSize[0])
+					collision := tileRight - rectX
+					if collision >= moveX {
+						moveX = collision
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.TileMap[y][x],
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{1, 0},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return moveX
+}
+
+// CollideY is the original CollideY
+func (c *refCollider[T]) CollideY(rectX, rectY, rectW, rectH, moveY float64) float64 {
+
+	checkLimit := max(1, int(math.Ceil(math.Abs(moveY)/float64(c.TileSize[1])))+1)
+
+	playerLeft := int(math.Floor(rectX / float64(c.TileSize[0])))
+	playerRight := int(math.Ceil((rectX+rectW)/float64(c.TileSize[0]))) - 1
+
+	if moveY > 0 {
+		startY := int(math.Floor((rectY + rectH) / float64(c.TileSize[1])))
+		endY := startY + checkLimit
+		endY = min(endY, len(c.TileMap))
+
+		for x := playerLeft; x <= playerRight; x++ {
+			if x < 0 || x >= len(c.TileMap[0]) {
+				continue
+			}
+			for y := startY; y < endY; y++ {
+				if y < 0 || y >= len(c.TileMap) {
+					continue
+				}
+				if c.TileMap[y][x] != c.NonSolidTileID {
+					tileTop := float64(y * c.TileSize[1])
+					collision := tileTop - (rectY + rectH)
+					if collision <= moveY {
+						moveY = collision
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.TileMap[y][x],
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{0, -1},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	if moveY < 0 {
+		endY := int(math.Floor(rectY / float64(c.TileSize[1])))
+		startY := endY - checkLimit
+		startY = max(startY, 0)
+
+		for x := playerLeft; x <= playerRight; x++ {
+			if x < 0 || x >= len(c.TileMap[0]) {
+				continue
+			}
+			for y := startY; y <= endY; y++ {
+				if y < 0 || y >= len(c.TileMap) {
+					continue
+				}
+				if c.TileMap[y][x] != c.NonSolidTileID {
+					tileBottom := float64((y + 1) * c.TileSize[1])
+					collision := tileBottom - rectY
+					if collision >= moveY {
+						moveY = collision
+						c.Collisions = append(c.Collisions, CollisionInfo[T]{
+							TileID:     c.TileMap[y][x],
+							TileCoords: [2]int{x, y},
+							Normal:     [2]int{0, 1},
+						})
+					}
+				}
+			}
+		}
+	}
+
+	return moveY
+}
+
+// randMap returns a w by h map where a quarter of the tiles are solid with IDs 1-3
+func randMap(r *rand.Rand, w, h int) [][]uint8 {
+	m := make([][]uint8, h)
+	for y := range m {
+		m[y] = make([]uint8, w)
+		for x := range m[y] {
+			if r.Intn(4) == 0 {
+				m[y][x] = uint8(1 + r.Intn(3))
+			}
+		}
+	}
+	return m
+}
+
+// collideCase is a random Collide call
+type collideCase struct {
+	static                   bool
+	x, y, w, h, moveX, moveY float64
+}
+
+func randCase(r *rand.Rand) collideCase {
+	tc := collideCase{
+		static: r.Intn(3) == 0,
+		x:      r.Float64()*300 - 50,
+		y:      r.Float64()*300 - 50,
+		w:      1 + r.Float64()*40,
+		h:      1 + r.Float64()*40,
+		moveX:  r.Float64()*80 - 40,
+		moveY:  r.Float64()*80 - 40,
+	}
+	if r.Intn(5) == 0 {
+		tc.moveX = 0
+	}
+	if r.Intn(5) == 0 {
+		tc.moveY = 0
+	}
+	if tc.static && r.Intn(2) == 0 {
+		tc.moveX, tc.moveY = 0, 0
+	}
+	return tc
+}
+
+// checkEquivalent runs random Collide calls on colliders created by newCollider and fails if any differs from refCollider
+func checkEquivalent(t *testing.T, iterations int, newCollider func(m [][]uint8, tw, th int) *Collider[uint8]) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	for i := range iterations {
+		m := randMap(r, 3+r.Intn(10), 3+r.Intn(10))
+		tw, th := 8+r.Intn(20), 8+r.Intn(20)
+		ref := &refCollider[uint8]{TileMap: m, TileSize: [2]int{tw, th}}
+		c := newCollider(m, tw, th)
+		tc := randCase(r)
+		ref.StaticCheck, c.StaticCheck = tc.static, tc.static
+
+		wantX, wantY := ref.Collide(tc.x, tc.y, tc.w, tc.h, tc.moveX, tc.moveY, nil)
+		gotX, gotY := c.Collide(tc.x, tc.y, tc.w, tc.h, tc.moveX, tc.moveY, nil)
+		if gotX != wantX || gotY != wantY {
+			t.Fatalf("case %d %+v: got %v,%v want %v,%v", i, tc, gotX, gotY, wantX, wantY)
+		}
+		if !slices.Equal(c.Collisions, ref.Collisions) {
+			t.Fatalf("case %d %+v: got collisions %v want %v", i, tc, c.Collisions, ref.Collisions)
+		}
+	}
+}
+
+func TestCollideMatchesReference(t *testing.T) {
+	checkEquivalent(t, 20000, func(m [][]uint8, tw, th int) *Collider[uint8] {
+		return NewCollider(m, tw, th)
+	})
+}
+
+var benchMap = randMap(rand.New(rand.NewSource(2)), 4096, 4096)
+
+// benchmarkCollide moves 30x30 rectangles from random positions of the 4096x4096 benchMap
+func benchmarkCollide(b *testing.B, collide func(x, y, w, h, moveX, moveY float64) (float64, float64)) {
+	r := rand.New(rand.NewSource(3))
+	for b.Loop() {
+		collide(r.Float64()*4096*16, r.Float64()*4096*16, 30, 30, r.Float64()*80-40, r.Float64()*80-40)
+	}
+}
+
+// BenchmarkCollideReference is the baseline for BenchmarkCollide.
+// The plain [][]T path must stay as fast as the original implementation.
+func BenchmarkCollideReference(b *testing.B) {
+	c := &refCollider[uint8]{TileMap: benchMap, TileSize: [2]int{16, 16}}
+	benchmarkCollide(b, func(x, y, w, h, moveX, moveY float64) (float64, float64) {
+		return c.Collide(x, y, w, h, moveX, moveY, nil)
+	})
+}
+
+func BenchmarkCollide(b *testing.B) {
+	c := NewCollider(benchMap, 16, 16)
+	benchmarkCollide(b, func(x, y, w, h, moveX, moveY float64) (float64, float64) {
+		return c.Collide(x, y, w, h, moveX, moveY, nil)
+	})
+}
+
+func BenchmarkCollideFluids(b *testing.B) {
+	c := NewCollider(benchMap, 16, 16)
+	c.Fluids = map[uint8]Fluid{3: {Buoyancy: 0.5, Drag: 0.1}}
+	benchmarkCollide(b, func(x, y, w, h, moveX, moveY float64) (float64, float64) {
+		return c.Collide(x, y, w, h, moveX, moveY, nil)
+	})
+}
+
+func BenchmarkCollideSolidFunc(b *testing.B) {
+	c := NewCollider(benchMap, 16, 16)
+	c.SolidFunc = func(id uint8) bool { return id != 0 && id != 2 }
+	benchmarkCollide(b, func(x, y, w, h, moveX, moveY float64) (float64, float64) {
+		return c.Collide(x, y, w, h, moveX, moveY, nil)
+	})
+}
@@ -0,0 +1,208 @@
+package tiled
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type jsonMap struct {
+	Width      int            `json:"width"`
+	Height     int            `json:"height"`
+	TileWidth  int            `json:"tilewidth"`
+	TileHeight int            `json:"tileheight"`
+	Infinite   bool           `json:"infinite"`
+	Properties []jsonProperty `json:"properties"`
+	Tilesets   []jsonTileset  `json:"tilesets"`
+	Layers     []jsonLayer    `json:"layers"`
+}
+
+type jsonTileset struct {
+	FirstGID uint32     `json:"firstgid"`
+	Name     string     `json:"name"`
+	Source   string     `json:"source"`
+	Tiles    []jsonTile `json:"tiles"`
+}
+
+type jsonTile struct {
+	ID         uint32         `json:"id"`
+	Properties []jsonProperty `json:"properties"`
+}
+
+type jsonLayer struct {
+	Type        string          `json:"type"`
+	Name        string          `json:"name"`
+	Width       int             `json:"width"`
+	Height      int             `json:"height"`
+	Encoding    string          `json:"encoding"`
+	Compression string          `json:"compression"`
+	Data        json.RawMessage `json:"data"`
+	Chunks      json.RawMessage `json:"chunks"`
+	Properties  []jsonProperty  `json:"properties"`
+	Layers      []jsonLayer     `json:"layers"`
+}
+
+type jsonProperty struct {
+	Name  string          `json:"name"`
+	Value json.RawMessage `json:"value"`
+}
+
+// LoadJSON loads a .tmj or .json map file. External tilesets are loaded relative to the map file.
+func LoadJSON(path string) (*Map, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := DecodeJSON(f)
+	if err != nil {
+		return nil, err
+	}
+	for _, ts := range m.Tilesets {
+		if ts.Source == "" {
+			continue
+		}
+		tsPath := filepath.Join(filepath.Dir(path), ts.Source)
+		if strings.EqualFold(filepath.Ext(tsPath), ".tsx") {
+			err = loadTSX(tsPath, ts)
+		} else {
+			err = loadTSJ(tsPath, ts)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// DecodeJSON decodes a map in Tiled JSON format. External tilesets are not loaded; only their FirstGID and Source are set.
+//
+// Supported layer encodings are CSV (JSON array) and base64 with optional zlib or gzip compression.
+func DecodeJSON(r io.Reader) (*Map, error) {
+	var jm jsonMap
+	if err := json.NewDecoder(r).Decode(&jm); err != nil {
+		return nil, fmt.Errorf("tiled: %w", err)
+	}
+	if jm.Infinite {
+		return nil, ErrInfiniteMap
+	}
+
+	m := &Map{
+		Width:      jm.Width,
+		Height:     jm.Height,
+		TileWidth:  jm.TileWidth,
+		TileHeight: jm.TileHeight,
+		Properties: jsonProperties(jm.Properties),
+	}
+	for _, t := range jm.Tilesets {
+		m.Tilesets = append(m.Tilesets, &Tileset{
+			FirstGID: t.FirstGID,
+			Name:     t.Name,
+			Source:   t.Source,
+			Tiles:    jsonTiles(t.Tiles),
+		})
+	}
+	sort.Slice(m.Tilesets, func(i, j int) bool { return m.Tilesets[i].FirstGID < m.Tilesets[j].FirstGID })
+
+	var addLayers func(layers []jsonLayer) error
+	addLayers = func(layers []jsonLayer) error {
+		for _, jl := range layers {
+			switch jl.Type {
+			case "group":
+				if err := addLayers(jl.Layers); err != nil {
+					return err
+				}
+			case "tilelayer":
+				data, err := jl.decode()
+				if err != nil {
+					return fmt.Errorf("tiled: layer %q: %w", jl.Name, err)
+				}
+				m.Layers = append(m.Layers, &Layer{
+					Name:       jl.Name,
+					Width:      jl.Width,
+					Height:     jl.Height,
+					Properties: jsonProperties(jl.Properties),
+					Data:       data,
+				})
+			}
+		}
+		return nil
+	}
+	if err := addLayers(jm.Layers); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// loadTSJ loads the tile properties of an external JSON tileset file into ts
+func loadTSJ(path string, ts *Tileset) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var t jsonTileset
+	if err := json.NewDecoder(f).Decode(&t); err != nil {
+		return fmt.Errorf("tiled: %s: %w", path, err)
+	}
+	ts.Name = t.Name
+	ts.Tiles = jsonTiles(t.Tiles)
+	return nil
+}
+
+// decode returns the GIDs of the layer data with the flip flags stripped
+func (l jsonLayer) decode() ([]uint32, error) {
+	if len(l.Chunks) > 0 && string(l.Chunks) != "null" {
+		return nil, ErrInfiniteMap
+	}
+
+	var gids []uint32
+	switch l.Encoding {
+	case "", "csv":
+		if err := json.Unmarshal(l.Data, &gids); err != nil {
+			return nil, err
+		}
+	case "base64":
+		var text string
+		if err := json.Unmarshal(l.Data, &text); err != nil {
+			return nil, err
+		}
+		var err error
+		if gids, err = decodeBase64(text, l.Compression); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", l.Encoding)
+	}
+
+	if len(gids) != l.Width*l.Height {
+		return nil, fmt.Errorf("got %d tiles, want %d", len(gids), l.Width*l.Height)
+	}
+	stripFlags(gids)
+	return gids, nil
+}
+
+func jsonProperties(props []jsonProperty) Properties {
+	p := make(Properties, len(props))
+	for _, prop := range props {
+		var s string
+		if err := json.Unmarshal(prop.Value, &s); err == nil {
+			p[prop.Name] = s
+		} else {
+			p[prop.Name] = string(prop.Value)
+		}
+	}
+	return p
+}
+
+func jsonTiles(tiles []jsonTile) map[uint32]Properties {
+	m := make(map[uint32]Properties, len(tiles))
+	for _, t := range tiles {
+		m[t.ID] = jsonProperties(t.Properties)
+	}
+	return m
+}
@@ -0,0 +1,72 @@
+package tiled
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLoadJSON(t *testing.T) {
+	m, err := LoadJSON("testdata/level.tmj")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLayers(t, m, "array", "base64", "zlib", "gzip")
+
+	if m.Properties["gravity"] != "9.8" {
+		t.Errorf("got map properties %v", m.Properties)
+	}
+	if len(m.Tilesets) != 2 || m.Tilesets[1].Name != "props" {
+		t.Fatalf("external tileset not loaded: %+v", m.Tilesets[1])
+	}
+
+	l, err := m.LayerByProperty("collision")
+	if err != nil || l.Name != "zlib" {
+		t.Fatalf("LayerByProperty got %v, %v", l, err)
+	}
+	c, err := NewCollider[uint16](m, l)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.TileSize != [2]int{16, 8} {
+		t.Errorf("got TileSize %v", c.TileSize)
+	}
+
+	// Tiles 3 (terrain) and 10 (props) have solid=true, tile 1 has solid=false and tile 2 has no properties
+	c.SolidFunc = SolidFunc[uint16](m, "solid")
+	for id, want := range map[uint16]bool{0: false, 1: false, 2: false, 3: true, 10: true, 11: false} {
+		if got := c.IsSolid(id); got != want {
+			t.Errorf("IsSolid(%d) = %v, want %v", id, got, want)
+		}
+	}
+
+	// Row 1 holds tiles 1 and 2, which are not solid. Row 2 is solid.
+	dx, _ := c.Collide(0, 8, 16, 8, 40, 0, nil)
+	if dx != 40 || len(c.Collisions) != 0 {
+		t.Errorf("got dx %v with collisions %v", dx, c.Collisions)
+	}
+	_, dy := c.Collide(16, 0, 16, 8, 0, 20, nil)
+	if dy != 8 || len(c.Collisions) != 1 || c.Collisions[0].TileCoords != [2]int{1, 2} {
+		t.Errorf("got dy %v with collisions %v", dy, c.Collisions)
+	}
+}
+
+func TestDecodeJSONErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want error
+	}{
+		{"infinite", `{"width":4,"height":3,"infinite":true}`, ErrInfiniteMap},
+		{"chunks", `{"layers":[{"type":"tilelayer","name":"a","chunks":[{"x":0}]}]}`, ErrInfiniteMap},
+		{"short", `{"layers":[{"type":"tilelayer","name":"a","width":2,"height":1,"data":[1]}]}`, nil},
+		{"encoding", `{"layers":[{"type":"tilelayer","name":"a","width":1,"height":1,"encoding":"hex","data":"01"}]}`, nil},
+		{"syntax", `{"layers":[`, nil},
+	}
+	for _, tt := range tests {
+		_, err := DecodeJSON(strings.NewReader(tt.src))
+		if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
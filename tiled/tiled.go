@@ -0,0 +1,153 @@
+// Package tiled loads Tiled (https://www.mapeditor.org) maps into tile grids for tilecollider.
+package tiled
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/setanarut/tilecollider"
+)
+
+// Flip flags stored in the highest bits of Tiled GIDs
+const (
+	FlipHorizontal uint32 = 0x80000000
+	FlipVertical   uint32 = 0x40000000
+	FlipDiagonal   uint32 = 0x20000000
+	RotatedHex120  uint32 = 0x10000000
+
+	// FlagsMask matches all flag bits of a GID
+	FlagsMask = FlipHorizontal | FlipVertical | FlipDiagonal | RotatedHex120
+)
+
+var (
+	ErrLayerNotFound = errors.New("tiled: layer not found")
+	ErrInfiniteMap   = errors.New("tiled: infinite maps are not supported")
+)
+
+// Map is a decoded Tiled map
+type Map struct {
+	Width      int        // Map width in tiles
+	Height     int        // Map height in tiles
+	TileWidth  int        // Width of tiles in pixels
+	TileHeight int        // Height of tiles in pixels
+	Properties Properties // Custom properties of the map
+	Tilesets   []*Tileset // Tilesets ordered by FirstGID
+	Layers     []*Layer   // Tile layers. Layers inside groups are flattened.
+}
+
+// Tileset is a decoded Tiled tileset
+type Tileset struct {
+	FirstGID uint32                // GID of the first tile of the tileset
+	Name     string                // Name of the tileset
+	Source   string                // Path of the external tileset file, if any
+	Tiles    map[uint32]Properties // Custom properties of tiles keyed by local tile ID
+}
+
+// Layer is a decoded Tiled tile layer
+type Layer struct {
+	Name       string     // Name of the layer
+	Width      int        // Layer width in tiles
+	Height     int        // Layer height in tiles
+	Properties Properties // Custom properties of the layer
+	Data       []uint32   // GIDs in row-major order with the flip flags stripped. 0 is an empty tile.
+}
+
+// Properties stores custom properties by name
+type Properties map[string]string
+
+// Bool returns the property as a boolean. Missing or invalid values are false.
+func (p Properties) Bool(name string) bool {
+	v, _ := strconv.ParseBool(p[name])
+	return v
+}
+
+// Layer returns the first tile layer with the given name
+func (m *Map) Layer(name string) (*Layer, error) {
+	for _, l := range m.Layers {
+		if l.Name == name {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %q", ErrLayerNotFound, name)
+}
+
+// LayerByProperty returns the first tile layer whose boolean custom property is true (e.g. "collision")
+func (m *Map) LayerByProperty(name string) (*Layer, error) {
+	for _, l := range m.Layers {
+		if l.Properties.Bool(name) {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: no layer with property %q", ErrLayerNotFound, name)
+}
+
+// Tileset returns the tileset containing the GID and the local tile ID inside it.
+// It returns nil for empty tiles.
+func (m *Map) Tileset(gid uint32) (ts *Tileset, localID uint32) {
+	gid &^= FlagsMask
+	for _, t := range m.Tilesets {
+		if t.FirstGID <= gid && (ts == nil || t.FirstGID > ts.FirstGID) {
+			ts = t
+		}
+	}
+	if ts == nil {
+		return nil, 0
+	}
+	return ts, gid - ts.FirstGID
+}
+
+// TileProperties returns the custom properties of the tile with the GID, or nil
+func (m *Map) TileProperties(gid uint32) Properties {
+	ts, id := m.Tileset(gid)
+	if ts == nil {
+		return nil
+	}
+	return ts.Tiles[id]
+}
+
+// TileMap converts the layer GIDs to a [y][x]T tilemap for tilecollider.NewCollider.
+// It returns an error if a GID does not fit in T.
+func TileMap[T tilecollider.Integer](l *Layer) ([][]T, error) {
+	if len(l.Data) != l.Width*l.Height {
+		return nil, fmt.Errorf("tiled: layer %q has %d tiles, want %d", l.Name, len(l.Data), l.Width*l.Height)
+	}
+	tileMap := make([][]T, l.Height)
+	for y := range tileMap {
+		tileMap[y] = make([]T, l.Width)
+		for x := range tileMap[y] {
+			gid := l.Data[y*l.Width+x]
+			id := T(gid)
+			if uint64(id) != uint64(gid) || id < 0 {
+				return nil, fmt.Errorf("tiled: GID %d at %d,%d of layer %q overflows tile type", gid, x, y, l.Name)
+			}
+			tileMap[y][x] = id
+		}
+	}
+	return tileMap, nil
+}
+
+// NewCollider creates a collider from the tile layer with TileSize taken from the map
+func NewCollider[T tilecollider.Integer](m *Map, l *Layer) (*tilecollider.Collider[T], error) {
+	tileMap, err := TileMap[T](l)
+	if err != nil {
+		return nil, err
+	}
+	return tilecollider.NewCollider(tileMap, m.TileWidth, m.TileHeight), nil
+}
+
+// SolidFunc returns a solidity rule for Collider.SolidFunc.
+// A tile ID is solid if the boolean custom property with the given name (e.g. "solid") of its tile is true.
+func SolidFunc[T tilecollider.Integer](m *Map, name string) func(id T) bool {
+	solid := make(map[uint32]bool)
+	for _, ts := range m.Tilesets {
+		for id, props := range ts.Tiles {
+			if props.Bool(name) {
+				solid[ts.FirstGID+id] = true
+			}
+		}
+	}
+	return func(id T) bool {
+		return id > 0 && solid[uint32(id)]
+	}
+}
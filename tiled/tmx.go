@@ -0,0 +1,270 @@
+package tiled
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type tmxMap struct {
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	TileWidth  int           `xml:"tilewidth,attr"`
+	TileHeight int           `xml:"tileheight,attr"`
+	Infinite   bool          `xml:"infinite,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Tilesets   []tmxTileset  `xml:"tileset"`
+	tmxGroup
+}
+
+type tmxGroup struct {
+	Layers []tmxLayer `xml:"layer"`
+	Groups []tmxGroup `xml:"group"`
+}
+
+type tmxTileset struct {
+	FirstGID uint32    `xml:"firstgid,attr"`
+	Name     string    `xml:"name,attr"`
+	Source   string    `xml:"source,attr"`
+	Tiles    []tmxTile `xml:"tile"`
+}
+
+type tmxTile struct {
+	ID         uint32        `xml:"id,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+}
+
+type tmxLayer struct {
+	Name       string        `xml:"name,attr"`
+	Width      int           `xml:"width,attr"`
+	Height     int           `xml:"height,attr"`
+	Properties []tmxProperty `xml:"properties>property"`
+	Data       tmxData       `xml:"data"`
+}
+
+type tmxData struct {
+	Encoding    string `xml:"encoding,attr"`
+	Compression string `xml:"compression,attr"`
+	Tiles       []struct {
+		GID uint32 `xml:"gid,attr"`
+	} `xml:"tile"`
+	Chunks []struct{} `xml:"chunk"`
+	Text   string     `xml:",chardata"`
+}
+
+type tmxProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+	Text  string `xml:",chardata"`
+}
+
+// LoadTMX loads a .tmx map file. External tilesets are loaded relative to the map file.
+func LoadTMX(path string) (*Map, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	m, err := DecodeTMX(f)
+	if err != nil {
+		return nil, err
+	}
+	for _, ts := range m.Tilesets {
+		if ts.Source == "" {
+			continue
+		}
+		if err := loadTSX(filepath.Join(filepath.Dir(path), ts.Source), ts); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// DecodeTMX decodes a map in TMX format. External tilesets are not loaded; only their FirstGID and Source are set.
+//
+// Supported layer encodings are XML, CSV and base64 with optional zlib or gzip compression.
+func DecodeTMX(r io.Reader) (*Map, error) {
+	var tm tmxMap
+	if err := xml.NewDecoder(r).Decode(&tm); err != nil {
+		return nil, fmt.Errorf("tiled: %w", err)
+	}
+	if tm.Infinite {
+		return nil, ErrInfiniteMap
+	}
+
+	m := &Map{
+		Width:      tm.Width,
+		Height:     tm.Height,
+		TileWidth:  tm.TileWidth,
+		TileHeight: tm.TileHeight,
+		Properties: tmxProperties(tm.Properties),
+	}
+	for _, t := range tm.Tilesets {
+		ts := &Tileset{FirstGID: t.FirstGID, Name: t.Name, Source: t.Source}
+		ts.Tiles = tmxTiles(t.Tiles)
+		m.Tilesets = append(m.Tilesets, ts)
+	}
+	sort.Slice(m.Tilesets, func(i, j int) bool { return m.Tilesets[i].FirstGID < m.Tilesets[j].FirstGID })
+
+	var addLayers func(g tmxGroup) error
+	addLayers = func(g tmxGroup) error {
+		for _, tl := range g.Layers {
+			data, err := tl.Data.decode(tl.Width * tl.Height)
+			if err != nil {
+				return fmt.Errorf("tiled: layer %q: %w", tl.Name, err)
+			}
+			m.Layers = append(m.Layers, &Layer{
+				Name:       tl.Name,
+				Width:      tl.Width,
+				Height:     tl.Height,
+				Properties: tmxProperties(tl.Properties),
+				Data:       data,
+			})
+		}
+		for _, sub := range g.Groups {
+			if err := addLayers(sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := addLayers(tm.tmxGroup); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// loadTSX loads the tile properties of an external tileset file into ts
+func loadTSX(path string, ts *Tileset) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	var t tmxTileset
+	if err := xml.NewDecoder(f).Decode(&t); err != nil {
+		return fmt.Errorf("tiled: %s: %w", path, err)
+	}
+	ts.Name = t.Name
+	ts.Tiles = tmxTiles(t.Tiles)
+	return nil
+}
+
+// decode returns the GIDs of the layer data with the flip flags stripped
+func (d tmxData) decode(size int) ([]uint32, error) {
+	if len(d.Chunks) > 0 {
+		return nil, ErrInfiniteMap
+	}
+
+	var gids []uint32
+	switch d.Encoding {
+	case "":
+		gids = make([]uint32, len(d.Tiles))
+		for i, t := range d.Tiles {
+			gids[i] = t.GID
+		}
+	case "csv":
+		for _, field := range strings.Split(d.Text, ",") {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				continue
+			}
+			gid, err := strconv.ParseUint(field, 10, 32)
+			if err != nil {
+				return nil, err
+			}
+			gids = append(gids, uint32(gid))
+		}
+	case "base64":
+		var err error
+		if gids, err = decodeBase64(d.Text, d.Compression); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported encoding %q", d.Encoding)
+	}
+
+	if len(gids) != size {
+		return nil, fmt.Errorf("got %d tiles, want %d", len(gids), size)
+	}
+	stripFlags(gids)
+	return gids, nil
+}
+
+// decodeBase64 decodes base64 layer data with optional compression
+func decodeBase64(text, compression string) ([]uint32, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(text))
+	if err != nil {
+		return nil, err
+	}
+	raw, err = decompress(raw, compression)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw)%4 != 0 {
+		return nil, fmt.Errorf("invalid data length %d", len(raw))
+	}
+	gids := make([]uint32, len(raw)/4)
+	for i := range gids {
+		gids[i] = binary.LittleEndian.Uint32(raw[i*4:])
+	}
+	return gids, nil
+}
+
+// decompress decompresses base64-decoded layer data
+func decompress(raw []byte, compression string) ([]byte, error) {
+	var r io.ReadCloser
+	var err error
+	switch compression {
+	case "":
+		return raw, nil
+	case "zlib":
+		r, err = zlib.NewReader(bytes.NewReader(raw))
+	case "gzip":
+		r, err = gzip.NewReader(bytes.NewReader(raw))
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", compression)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// stripFlags removes the flip flags from the GIDs
+func stripFlags(gids []uint32) {
+	for i := range gids {
+		gids[i] &^= FlagsMask
+	}
+}
+
+func tmxProperties(props []tmxProperty) Properties {
+	p := make(Properties, len(props))
+	for _, prop := range props {
+		if prop.Value == "" {
+			p[prop.Name] = prop.Text
+		} else {
+			p[prop.Name] = prop.Value
+		}
+	}
+	return p
+}
+
+func tmxTiles(tiles []tmxTile) map[uint32]Properties {
+	m := make(map[uint32]Properties, len(tiles))
+	for _, t := range tiles {
+		m[t.ID] = tmxProperties(t.Properties)
+	}
+	return m
+}
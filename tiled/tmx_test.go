@@ -0,0 +1,92 @@
+package tiled
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// wantTiles is the content of every tile layer of the testdata maps. Some GIDs are stored with flip flags.
+var wantTiles = [][]uint16{
+	{0, 0, 0, 10},
+	{1, 0, 2, 0},
+	{3, 3, 3, 3},
+}
+
+// checkLayers checks that every named layer of the map decodes to wantTiles
+func checkLayers(t *testing.T, m *Map, names ...string) {
+	t.Helper()
+	if m.Width != 4 || m.Height != 3 || m.TileWidth != 16 || m.TileHeight != 8 {
+		t.Fatalf("got map size %dx%d tiles %dx%d", m.Width, m.Height, m.TileWidth, m.TileHeight)
+	}
+	for _, name := range names {
+		l, err := m.Layer(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := TileMap[uint16](l)
+		if err != nil {
+			t.Fatalf("layer %q: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, wantTiles) {
+			t.Errorf("layer %q: got %v want %v", name, got, wantTiles)
+		}
+	}
+}
+
+func TestLoadTMX(t *testing.T) {
+	m, err := LoadTMX("testdata/level.tmx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLayers(t, m, "xml", "csv", "base64", "zlib", "gzip")
+
+	if m.Properties["gravity"] != "9.8" {
+		t.Errorf("got map properties %v", m.Properties)
+	}
+	l, err := m.LayerByProperty("collision")
+	if err != nil || l.Name != "zlib" {
+		t.Errorf("LayerByProperty got %v, %v", l, err)
+	}
+	if len(m.Tilesets) != 2 || m.Tilesets[1].Name != "props" || m.Tilesets[1].Source != "props.tsx" {
+		t.Fatalf("external tileset not loaded: %+v", m.Tilesets[1])
+	}
+	if ts, id := m.Tileset(11 | FlipHorizontal); ts != m.Tilesets[1] || id != 1 {
+		t.Errorf("Tileset(11) got %v, %d", ts, id)
+	}
+	if !m.TileProperties(10).Bool("solid") || !m.TileProperties(3).Bool("solid") || m.TileProperties(1).Bool("solid") {
+		t.Error("tile properties not decoded")
+	}
+}
+
+func TestDecodeTMXErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want error
+	}{
+		{"infinite", `<map width="4" height="3" infinite="1"></map>`, ErrInfiniteMap},
+		{"chunks", `<map width="4" height="3"><layer name="a" width="4" height="3"><data encoding="csv"><chunk x="0" y="0"/></data></layer></map>`, ErrInfiniteMap},
+		{"short", `<map width="2" height="1"><layer name="a" width="2" height="1"><data encoding="csv">1</data></layer></map>`, nil},
+		{"encoding", `<map width="1" height="1"><layer name="a" width="1" height="1"><data encoding="hex">01</data></layer></map>`, nil},
+		{"compression", `<map width="1" height="1"><layer name="a" width="1" height="1"><data encoding="base64" compression="zstd">AQAAAA==</data></layer></map>`, nil},
+	}
+	for _, tt := range tests {
+		_, err := DecodeTMX(strings.NewReader(tt.src))
+		if err == nil || (tt.want != nil && !errors.Is(err, tt.want)) {
+			t.Errorf("%s: got error %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	m, err := LoadTMX("testdata/level.tmx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Layer("missing"); !errors.Is(err, ErrLayerNotFound) {
+		t.Errorf("Layer got %v", err)
+	}
+	if _, err := TileMap[int8](&Layer{Name: "a", Width: 1, Height: 1, Data: []uint32{200}}); err == nil {
+		t.Error("GID 200 fits in int8")
+	}
+}